@@ -0,0 +1,130 @@
+package rb
+
+import (
+	"context"
+	"time"
+)
+
+// CapacityController resizes a ring buffer to suit its workload. At every
+// check, it grows the ring buffer if too many values were dropped since the
+// last check, and shrinks it if it was underfilled for several consecutive
+// checks, so operators don't have to tune sizes by hand.
+//
+// Only values evicted to make room for new values count as dropped, not values
+// which leave the ring buffer otherwise, e.g. by TTL expiry, DrainTo, or Clear.
+// Shrinking never drops values.
+type CapacityController[T any] struct {
+	// Min and Max bound the size of the ring buffer. If Min is zero, 1 is used.
+	// If Max is zero, the ring buffer is never grown.
+	Min, Max int
+
+	// DropThreshold is the fraction of values added since the last check which
+	// may be dropped before the ring buffer is doubled. If it's zero, 0.01 is
+	// used.
+	DropThreshold float64
+
+	// FillThreshold is the fraction of the capacity below which the ring buffer
+	// is considered underfilled. If it's zero, 0.25 is used.
+	FillThreshold float64
+
+	// ShrinkAfter is the number of consecutive underfilled checks after which
+	// the ring buffer is halved. If it's zero, 10 is used.
+	ShrinkAfter int
+
+	// Interval is how often the ring buffer is checked by Run. If it's zero,
+	// 10s is used.
+	Interval time.Duration
+
+	// OnResize, if non-nil, is called with the old and new size, each time the
+	// ring buffer is resized.
+	OnResize func(from, to int)
+
+	rb          *RingBuffer[T]
+	added       uint64 // values added as of the last check
+	evicted     uint64 // values evicted as of the last check
+	underfilled int    // consecutive underfilled checks
+}
+
+// NewCapacityController returns a capacity controller for the ring buffer.
+// Exported fields should be set before calling Run or Check.
+func NewCapacityController[T any](rb *RingBuffer[T]) *CapacityController[T] {
+	rb.lock()
+	defer rb.unlock()
+
+	return &CapacityController[T]{
+		rb:      rb,
+		added:   rb.seq,
+		evicted: rb.evicted,
+	}
+}
+
+// Run checks the ring buffer at every interval, until the context is done.
+func (c *CapacityController[T]) Run(ctx context.Context) error {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.Check()
+		}
+	}
+}
+
+// Check the ring buffer once, resize it if necessary, and return its size.
+// It's not safe to call Check concurrently, including with Run.
+func (c *CapacityController[T]) Check() int {
+	var (
+		minSize       = max(1, c.Min)
+		dropThreshold = c.DropThreshold
+		fillThreshold = c.FillThreshold
+		shrinkAfter   = c.ShrinkAfter
+	)
+	if dropThreshold <= 0 {
+		dropThreshold = 0.01
+	}
+	if fillThreshold <= 0 {
+		fillThreshold = 0.25
+	}
+	if shrinkAfter <= 0 {
+		shrinkAfter = 10
+	}
+
+	c.rb.lock()
+	added, evicted, count, size := c.rb.seq, c.rb.evicted, c.rb.len, len(c.rb.buf)
+	c.rb.unlock()
+
+	delta := added - c.added
+	dropped := evicted - c.evicted
+	c.added, c.evicted = added, evicted
+
+	if float64(count) < fillThreshold*float64(size) {
+		c.underfilled++
+	} else {
+		c.underfilled = 0
+	}
+
+	var to int
+	switch {
+	case delta > 0 && float64(dropped) > dropThreshold*float64(delta) && size < c.Max:
+		to = min(c.Max, 2*size)
+	case c.underfilled >= shrinkAfter && size > minSize:
+		to = max(minSize, size/2, count)
+	default:
+		return size
+	}
+
+	c.underfilled = 0
+	c.rb.Resize(to)
+	if c.OnResize != nil {
+		c.OnResize(size, to)
+	}
+	return to
+}
@@ -0,0 +1,82 @@
+package rb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestCapacityController(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](10)
+	c := rb.NewCapacityController(buf)
+	c.Min, c.Max = 4, 25
+	c.FillThreshold, c.ShrinkAfter = 0.5, 2
+
+	var resizes [][2]int
+	c.OnResize = func(from, to int) { resizes = append(resizes, [2]int{from, to}) }
+
+	// Filling the ring buffer without drops doesn't grow it.
+	for i := range 10 {
+		buf.Add(i)
+	}
+	assertEqual(t, 10, c.Check())
+
+	// Drops grow it, up to the max.
+	for i := range 5 {
+		buf.Add(i)
+	}
+	assertEqual(t, 20, c.Check())
+	for i := range 30 {
+		buf.Add(i)
+	}
+	assertEqual(t, 25, c.Check())
+	for i := range 30 {
+		buf.Add(i)
+	}
+	assertEqual(t, 25, c.Check())
+
+	// Chronic underfilling shrinks it, down to the min, without dropping values.
+	buf.TrimOldest(0.9)
+	assertEqual(t, 25, c.Check())
+	assertEqual(t, 12, c.Check())
+	assertEqual(t, 12, c.Check())
+	assertEqual(t, 6, c.Check())
+	assertEqual(t, 6, c.Check())
+	assertEqual(t, 4, c.Check())
+	_, _, count := buf.Overview()
+	assertEqual(t, 2, count)
+
+	assertEqual(t, [][2]int{{10, 20}, {20, 25}, {25, 12}, {12, 6}, {6, 4}}, resizes)
+}
+
+func TestCapacityControllerDrained(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](10)
+	c := rb.NewCapacityController(buf)
+	c.Max = 100
+
+	// Values which are drained, or cleared, aren't dropped, so a consumer
+	// keeping up with a busy writer doesn't grow the ring buffer.
+	ch := make(chan int, 100)
+	for range 5 {
+		for i := range 10 {
+			buf.Add(i)
+		}
+		buf.DrainTo(context.Background(), ch)
+	}
+	for i := range 10 {
+		buf.Add(i)
+	}
+	buf.Clear()
+	assertEqual(t, 10, c.Check())
+
+	// Overwritten values are.
+	for i := range 20 {
+		buf.Add(i)
+	}
+	assertEqual(t, 20, c.Check())
+}
@@ -0,0 +1,136 @@
+package rb
+
+import (
+	"sync"
+)
+
+// BacklogPolicy determines what a Broadcaster does when a subscriber's channel
+// is full, i.e. the subscriber isn't keeping up with new values.
+type BacklogPolicy int
+
+const (
+	// DropNew drops the new value, for that subscriber only.
+	DropNew BacklogPolicy = iota
+
+	// SkipToLatest discards any values pending in the subscriber's channel,
+	// and replaces them with the new value.
+	SkipToLatest
+
+	// Disconnect closes the subscriber's channel, ending the subscription.
+	Disconnect
+)
+
+// Broadcaster is a ring buffer that also fans out every added value to any
+// number of subscribers, each of which receives values on its own channel, and
+// has its own backlog policy.
+//
+// It's safe for concurrent use by multiple goroutines.
+type Broadcaster[T any] struct {
+	rb *RingBuffer[T]
+
+	mtx  sync.Mutex
+	subs map[*subscription[T]]struct{}
+}
+
+type subscription[T any] struct {
+	c      chan T
+	policy BacklogPolicy
+}
+
+// NewBroadcaster returns a broadcaster with an underlying ring buffer of size sz.
+func NewBroadcaster[T any](sz int) *Broadcaster[T] {
+	return &Broadcaster[T]{
+		rb:   NewRingBuffer[T](sz),
+		subs: map[*subscription[T]]struct{}{},
+	}
+}
+
+// RingBuffer returns the underlying ring buffer, which contains recent values.
+// Values should be added via the broadcaster, not directly to the ring buffer.
+func (b *Broadcaster[T]) RingBuffer() *RingBuffer[T] {
+	return b.rb
+}
+
+// Add the value to the underlying ring buffer, and send it to every subscriber.
+// Add never blocks on subscribers: if a subscriber's channel is full, its
+// backlog policy determines what happens.
+func (b *Broadcaster[T]) Add(val T) (dropped T, ok bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	dropped, ok = b.rb.Add(val)
+
+	for sub := range b.subs {
+		b.send(sub, val)
+	}
+
+	return dropped, ok
+}
+
+// Subscribe returns a channel, with the given buffer size, that will receive
+// every value subsequently added to the broadcaster. The channel is closed when
+// the returned cancel function is called, or when the subscriber falls behind,
+// if the policy is Disconnect. The buffer size is at least 1.
+func (b *Broadcaster[T]) Subscribe(buffer int, policy BacklogPolicy) (c <-chan T, cancel func()) {
+	sub := &subscription[T]{
+		c:      make(chan T, max(1, buffer)),
+		policy: policy,
+	}
+
+	b.mtx.Lock()
+	b.subs[sub] = struct{}{}
+	b.mtx.Unlock()
+
+	return sub.c, func() {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		b.remove(sub)
+	}
+}
+
+// Close ends every subscription, closing each subscriber's channel.
+func (b *Broadcaster[T]) Close() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+}
+
+// send assumes the lock is held.
+func (b *Broadcaster[T]) send(sub *subscription[T], val T) {
+	select {
+	case sub.c <- val:
+		return
+	default:
+	}
+
+	switch sub.policy {
+	case DropNew:
+		// Nothing to do.
+
+	case SkipToLatest:
+		for len(sub.c) > 0 {
+			select {
+			case <-sub.c:
+			default:
+			}
+		}
+		select {
+		case sub.c <- val:
+		default:
+		}
+
+	case Disconnect:
+		b.remove(sub)
+	}
+}
+
+// remove assumes the lock is held.
+func (b *Broadcaster[T]) remove(sub *subscription[T]) {
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.c)
+	}
+}
@@ -0,0 +1,60 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	b := rb.NewBroadcaster[int](10)
+
+	drop, cancelDrop := b.Subscribe(2, rb.DropNew)
+	defer cancelDrop()
+	skip, cancelSkip := b.Subscribe(2, rb.SkipToLatest)
+	defer cancelSkip()
+	disc, cancelDisc := b.Subscribe(2, rb.Disconnect)
+	defer cancelDisc()
+
+	for i := range 5 {
+		b.Add(i)
+	}
+
+	assertEqual(t, []int{0, 1}, drain(drop))
+	assertEqual(t, []int{4}, drain(skip))
+	assertEqual(t, []int{0, 1}, drain(disc))
+
+	// The disconnected subscriber's channel is closed.
+	_, ok := <-disc
+	assertEqual(t, false, ok)
+
+	// Everything is still in the ring buffer.
+	vals, _ := b.RingBuffer().Take(10)
+	assertEqual(t, []int{4, 3, 2, 1, 0}, vals)
+
+	// Canceling closes the channel, and is idempotent with Close.
+	cancelDrop()
+	_, ok = <-drop
+	assertEqual(t, false, ok)
+	b.Close()
+	_, ok = <-skip
+	assertEqual(t, false, ok)
+}
+
+// drain returns all of the values immediately available on the channel.
+func drain[T any](c <-chan T) []T {
+	var vals []T
+	for {
+		select {
+		case val, ok := <-c:
+			if !ok {
+				return vals
+			}
+			vals = append(vals, val)
+		default:
+			return vals
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package rb
+
+// Buffer is the core set of operations provided by a ring buffer. RingBuffer
+// implements it, and so can other implementations, or test fakes. Application
+// code which only needs these operations should accept a Buffer.
+//
+// Implementations must be safe for concurrent use by multiple goroutines, and
+// should follow the semantics documented on the corresponding RingBuffer
+// methods, in particular that values are provided newest first.
+type Buffer[T any] interface {
+	Add(val T) (dropped T, ok bool)
+	Walk(fn func(T) error) error
+	Take(n int) ([]T, error)
+	Overview() (newest, oldest T, count int)
+	Resize(sz int) (dropped []T)
+}
+
+var _ Buffer[int] = (*RingBuffer[int])(nil)
@@ -0,0 +1,32 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestBuffer(t *testing.T) {
+	t.Parallel()
+
+	// Application code can be written against the interface.
+	latest := func(b rb.Buffer[string], n int) []string {
+		vals, err := b.Take(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return vals
+	}
+
+	var b rb.Buffer[string] = rb.NewRingBuffer[string](3)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		b.Add(s)
+	}
+	assertEqual(t, []string{"d", "c"}, latest(b, 2))
+
+	assertEqual(t, []string{"b"}, b.Resize(2))
+	newest, oldest, count := b.Overview()
+	assertEqual(t, "d", newest)
+	assertEqual(t, "c", oldest)
+	assertEqual(t, 2, count)
+}
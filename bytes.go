@@ -0,0 +1,64 @@
+package rb
+
+import (
+	"io"
+)
+
+// ByteRingBuffer adapts a ring buffer of bytes to io.Writer and io.WriterTo,
+// which is useful for capturing the most recent part of a byte stream, e.g.
+// the tail of a log, or of a process's output.
+type ByteRingBuffer struct {
+	*RingBuffer[byte]
+}
+
+var (
+	_ io.Writer   = ByteRingBuffer{}
+	_ io.WriterTo = ByteRingBuffer{}
+)
+
+// NewByteRingBuffer returns an empty ring buffer of sz bytes.
+func NewByteRingBuffer(sz int) ByteRingBuffer {
+	return ByteRingBuffer{NewRingBuffer[byte](sz)}
+}
+
+// Write adds all of the bytes in p to the ring buffer, in order, under a single
+// acquisition of the lock. It never fails. If p is larger than the ring buffer,
+// only its final bytes are kept.
+func (b ByteRingBuffer) Write(p []byte) (int, error) {
+	b.lock()
+	defer b.unlock()
+
+	// Waiters are notified once, rather than for every byte.
+	seq := b.seq
+	for _, c := range p {
+		b.put(c)
+	}
+	if b.seq != seq {
+		b.changed()
+	}
+
+	return len(p), nil
+}
+
+// WriteTo writes the contents of the ring buffer to w, oldest first, i.e. in
+// the order they were written. It writes directly from the ring buffer's
+// memory, without an intermediate copy, in at most two calls to w.Write, and
+// holds the lock while it does, so w should be fast. The ring buffer isn't
+// modified.
+func (b ByteRingBuffer) WriteTo(w io.Writer) (int64, error) {
+	b.lock()
+	defer b.unlock()
+
+	var total int64
+	for _, segment := range b.segments() {
+		if len(segment) == 0 {
+			continue
+		}
+		n, err := w.Write(segment)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,54 @@
+package rb_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestByteRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	b := rb.NewByteRingBuffer(10)
+
+	var buf bytes.Buffer
+	n, err := b.WriteTo(&buf)
+	assertEqual(t, int64(0), n)
+	assertEqual(t, error(nil), err)
+
+	fmt.Fprintf(b, "hello")
+	buf.Reset()
+	b.WriteTo(&buf)
+	assertEqual(t, "hello", buf.String())
+
+	// Wrapping around is written with two calls.
+	fmt.Fprintf(b, ", world!")
+	w := &countingWriter{}
+	n, err = b.WriteTo(w)
+	assertEqual(t, int64(10), n)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, "lo, world!", w.String())
+	assertEqual(t, 2, w.writes)
+
+	// The ring buffer isn't modified.
+	buf.Reset()
+	b.WriteTo(&buf)
+	assertEqual(t, "lo, world!", buf.String())
+
+	// A write is a single change.
+	gen := b.Generation()
+	fmt.Fprintf(b, "abc")
+	assertEqual(t, gen+1, b.Generation())
+}
+
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
@@ -0,0 +1,83 @@
+package rb
+
+import (
+	"cmp"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TopCategories returns the categories of the k ring buffers with the highest
+// scores, highest first. Ties are broken by category name. If score is nil,
+// ring buffers are scored by their current length.
+func (rbs *RingBuffers[T]) TopCategories(k int, score func(*RingBuffer[T]) float64) []string {
+	if score == nil {
+		score = func(rb *RingBuffer[T]) float64 {
+			_, _, count := rb.Overview()
+			return float64(count)
+		}
+	}
+
+	type scored struct {
+		category string
+		score    float64
+	}
+	var all []scored
+	for category, rb := range rbs.GetAll() {
+		all = append(all, scored{category, score(rb)})
+	}
+
+	slices.SortFunc(all, func(a, b scored) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.category, b.category)
+	})
+
+	top := make([]string, min(max(0, k), len(all)))
+	for i := range top {
+		top[i] = all[i].category
+	}
+	return top
+}
+
+// Match returns the ring buffers whose categories match the pattern. A pattern
+// which begins and ends with a slash, like /^http\./, is a regular expression,
+// see package regexp. Any other pattern is a glob, like tenant-42/*, see
+// path.Match, in which * doesn't match the / separator.
+func (rbs *RingBuffers[T]) Match(pattern string) (map[string]*RingBuffer[T], error) {
+	match, err := matcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := map[string]*RingBuffer[T]{}
+	for category, rb := range rbs.GetAll() {
+		if match(category) {
+			matched[category] = rb
+		}
+	}
+	return matched, nil
+}
+
+// matcher returns a function which reports whether a category matches the
+// pattern, as described by Match.
+func matcher(pattern string) (func(string) bool, error) {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+
+	// Check the pattern, so it's an error up front, rather than per category.
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return func(category string) bool {
+		ok, _ := path.Match(pattern, category)
+		return ok
+	}, nil
+}
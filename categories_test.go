@@ -0,0 +1,57 @@
+package rb_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestTopCategories(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int](3)
+	for category, n := range map[string]int{"quiet": 1, "noisy": 10, "medium": 4, "also-medium": 4} {
+		buf := rbs.GetOrCreate(category)
+		for i := range n {
+			buf.Add(i)
+		}
+	}
+
+	// By default, categories are scored by length, and all but one are full.
+	assertEqual(t, []string{"also-medium", "medium"}, rbs.TopCategories(2, nil))
+	assertEqual(t, []string{"also-medium", "medium", "noisy", "quiet"}, rbs.TopCategories(10, nil))
+
+	// By the number of values added.
+	added := func(buf *rb.RingBuffer[int]) float64 { return float64(buf.Stats().Added) }
+	assertEqual(t, []string{"noisy", "also-medium"}, rbs.TopCategories(2, added))
+
+	assertEqual(t, []string{}, rbs.TopCategories(0, nil))
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int](3)
+	for _, category := range []string{"http.in", "http.out", "grpc.in", "tenant-42/a", "tenant-42/b/c", "tenant-7/a"} {
+		rbs.GetOrCreate(category)
+	}
+
+	match := func(pattern string) []string {
+		t.Helper()
+		matched, err := rbs.Match(pattern)
+		assertEqual(t, error(nil), err)
+		return slices.Sorted(maps.Keys(matched))
+	}
+
+	assertEqual(t, []string{"http.in", "http.out"}, match("http.*"))
+	assertEqual(t, []string{"tenant-42/a"}, match("tenant-42/*"))
+	assertEqual(t, []string{"grpc.in", "http.in"}, match(`/\.in$/`))
+	assertEqual(t, 0, len(match("nope")))
+
+	_, err := rbs.Match("[")
+	assertEqual(t, true, err != nil)
+	_, err = rbs.Match("/(/")
+	assertEqual(t, true, err != nil)
+}
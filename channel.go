@@ -0,0 +1,62 @@
+package rb
+
+import (
+	"context"
+)
+
+// DrainTo removes values from the ring buffer, oldest first, and sends them to
+// the channel, until the ring buffer is empty, or the context is done. It
+// returns the number of values sent. Values are only removed from the ring
+// buffer after they're successfully sent, so if the context is done first, the
+// remaining values stay in the ring buffer. The lock isn't held while sending.
+func (rb *RingBuffer[T]) DrainTo(ctx context.Context, ch chan<- T) (int, error) {
+	var n int
+	for {
+		rb.lock()
+		if rb.len == 0 {
+			rb.unlock()
+			return n, nil
+		}
+		val, seq := rb.buf[rb.oldest()], rb.seqAt(rb.len-1)
+		rb.unlock()
+
+		select {
+		case ch <- val:
+			n += 1
+		case <-ctx.Done():
+			return n, ctx.Err()
+		}
+
+		// The value may have been overwritten or cleared while it was being
+		// sent, in which case there's nothing to remove.
+		rb.lock()
+		if rb.len > 0 && rb.seqAt(rb.len-1) == seq {
+			rb.dropOldest()
+			rb.changed()
+		}
+		rb.unlock()
+	}
+}
+
+// Consume adds every value received from the channel to the ring buffer, until
+// the channel is closed, or the context is done. It returns the number of
+// values received, and the number of values that were dropped, i.e. overwritten,
+// as a result. If the channel is closed, the returned error is nil; otherwise,
+// it's the context error.
+func (rb *RingBuffer[T]) Consume(ctx context.Context, ch <-chan T) (received, dropped int, err error) {
+	for {
+		select {
+		case val, ok := <-ch:
+			if !ok {
+				return received, dropped, nil
+			}
+			received += 1
+			if _, ok := rb.Add(val); ok {
+				dropped += 1
+			}
+
+		case <-ctx.Done():
+			return received, dropped, ctx.Err()
+		}
+	}
+}
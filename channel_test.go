@@ -0,0 +1,66 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestDrainTo(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](5)
+	for i := range 7 {
+		rb.Add(i)
+	}
+
+	// The channel can only take 3 values, so the context times out.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	ch := make(chan int, 3)
+	n, err := rb.DrainTo(ctx, ch)
+	assertEqual(t, 3, n)
+	assertEqual(t, true, errors.Is(err, context.DeadlineExceeded))
+	assertEqual(t, []int{2, 3, 4}, drain(ch))
+
+	// Unsent values remain in the ring buffer.
+	vals, _ := rb.Take(10)
+	assertEqual(t, []int{6, 5}, vals)
+
+	n, err = rb.DrainTo(context.Background(), ch)
+	assertEqual(t, 2, n)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []int{5, 6}, drain(ch))
+
+	vals, _ = rb.Take(10)
+	assertEqual(t, []int{}, vals)
+}
+
+func TestConsume(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](3)
+
+	ch := make(chan int, 10)
+	for i := range 5 {
+		ch <- i
+	}
+	close(ch)
+
+	received, dropped, err := rb.Consume(context.Background(), ch)
+	assertEqual(t, 5, received)
+	assertEqual(t, 2, dropped)
+	assertEqual(t, error(nil), err)
+
+	vals, _ := rb.Take(10)
+	assertEqual(t, []int{4, 3, 2}, vals)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = rb.Consume(ctx, make(chan int))
+	assertEqual(t, true, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,36 @@
+package rb
+
+// Checkpoint marks a point in the history of a ring buffer, see Mark.
+type Checkpoint struct {
+	seq uint64
+}
+
+// Mark returns a checkpoint representing the current state of the ring buffer.
+func (rb *RingBuffer[T]) Mark() Checkpoint {
+	rb.lock()
+	defer rb.unlock()
+
+	return Checkpoint{seq: rb.seq}
+}
+
+// SinceCheckpoint returns all of the values added to the ring buffer since the
+// checkpoint was marked, newest first. If all of those values are still in the
+// ring buffer, it returns true. If some of them have since been dropped, e.g.
+// overwritten by subsequent adds, it returns the values which remain, and false.
+// Either way, a new checkpoint from Mark will pick up where this one left off.
+func (rb *RingBuffer[T]) SinceCheckpoint(cp Checkpoint) ([]T, bool) {
+	rb.lock()
+	defer rb.unlock()
+
+	var added uint64
+	if rb.seq > cp.seq {
+		added = rb.seq - cp.seq
+	}
+
+	n := rb.countSince(cp.seq)
+	ok := uint64(n) == added
+
+	vals := make([]T, n)
+	rb.copy(vals)
+	return vals, ok
+}
@@ -0,0 +1,43 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](3)
+	rb.Add(1)
+
+	cp := rb.Mark()
+	vals, ok := rb.SinceCheckpoint(cp)
+	assertEqual(t, []int{}, vals)
+	assertEqual(t, true, ok)
+
+	rb.Add(2)
+	rb.Add(3)
+	vals, ok = rb.SinceCheckpoint(cp)
+	assertEqual(t, []int{3, 2}, vals)
+	assertEqual(t, true, ok)
+
+	rb.Add(4)
+	vals, ok = rb.SinceCheckpoint(cp)
+	assertEqual(t, []int{4, 3, 2}, vals)
+	assertEqual(t, true, ok)
+
+	rb.Add(5)
+	vals, ok = rb.SinceCheckpoint(cp)
+	assertEqual(t, []int{5, 4, 3}, vals)
+	assertEqual(t, false, ok)
+
+	cp = rb.Mark()
+	rb.Add(6)
+	rb.Clear()
+	rb.Add(7)
+	vals, ok = rb.SinceCheckpoint(cp)
+	assertEqual(t, []int{7}, vals)
+	assertEqual(t, false, ok)
+}
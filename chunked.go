@@ -0,0 +1,101 @@
+package rb
+
+import (
+	"runtime"
+)
+
+// CopyChunked is like Copy, but copies at most chunk values per acquisition of
+// the lock, and releases it between chunks, which bounds the time that writers
+// can be blocked when dst is large. The trade-off is consistency: Copy returns
+// a snapshot of the ring buffer at a single point in time, but CopyChunked can
+// observe modifications made between chunks. Specifically, it copies values
+// which were in the ring buffer when it started, newest first, skipping any
+// which were dropped before they were reached. Values added after it starts
+// aren't copied.
+//
+// The consistent result is true if the ring buffer wasn't modified while the
+// copy was in progress, i.e. dst holds a snapshot, as if from Copy. If chunk
+// <= 0, or chunk >= len(dst), the copy is done in a single chunk, and is always
+// consistent, so the mode can be selected by the caller via chunk.
+func (rb *RingBuffer[T]) CopyChunked(dst []T, chunk int) (n int, consistent bool) {
+	if chunk <= 0 || chunk >= len(dst) {
+		n, _ = rb.Copy(dst)
+		return n, true
+	}
+
+	rb.lock()
+	next, gen := rb.seq, rb.gen
+	rb.unlock()
+
+	consistent = true
+	for n < len(dst) {
+		rb.lock()
+
+		if rb.gen != gen {
+			consistent = false
+		}
+
+		i := rb.countSince(next)
+		for end := n + chunk; n < end && n < len(dst) && i < rb.len; n, i = n+1, i+1 {
+			dst[n] = rb.buf[rb.index(i)]
+			next = rb.seqAt(i)
+		}
+		done := i >= rb.len
+
+		rb.unlock()
+
+		if done {
+			break
+		}
+	}
+
+	return n, consistent
+}
+
+// WalkBatched is like Walk, but visits at most batch values per acquisition of
+// the lock, and releases it between batches, so a slow walk doesn't block
+// writers, or other readers, for its whole duration. Other goroutines are given
+// a chance to acquire the lock before the next batch, so writers take priority
+// over the walk. Like CopyChunked, it visits values which were in the ring
+// buffer when it started, newest first, skipping any which were dropped before
+// they were reached. If batch <= 0, it's equivalent to Walk.
+func (rb *RingBuffer[T]) WalkBatched(batch int, fn func(T) error) (err error) {
+	if batch <= 0 {
+		return rb.Walk(fn)
+	}
+
+	defer recoverPanic(&err)
+
+	rb.lock()
+	next := rb.seq
+	rb.unlock()
+
+	for {
+		done, err := rb.walkBatch(&next, batch, fn)
+		if err != nil || done {
+			return err
+		}
+		runtime.Gosched()
+	}
+}
+
+// walkBatch visits up to batch values with sequence numbers less than next,
+// newest first, and updates next. It reports whether there are no more values.
+func (rb *RingBuffer[T]) walkBatch(next *uint64, batch int, fn func(T) error) (done bool, err error) {
+	op := rb.startOp("Walk")
+	defer op.done()
+
+	rb.walk(func() {
+		op.acquired()
+		i := rb.countSince(*next)
+		for end := i + batch; i < end && i < rb.len; i++ {
+			*next = rb.seqAt(i)
+			if err = fn(rb.buf[rb.index(i)]); err != nil {
+				done = true
+				return
+			}
+		}
+		done = i >= rb.len
+	})
+	return done, err
+}
@@ -0,0 +1,107 @@
+package rb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferCopyChunked(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](10)
+	for i := range 10 {
+		rb.Add(i)
+	}
+
+	for _, chunk := range []int{-1, 0, 1, 3, 7, 10, 20} {
+		dst := make([]int, 8)
+		n, consistent := rb.CopyChunked(dst, chunk)
+		assertEqual(t, 8, n)
+		assertEqual(t, true, consistent)
+		assertEqual(t, []int{9, 8, 7, 6, 5, 4, 3, 2}, dst)
+	}
+
+	dst := make([]int, 20)
+	n, consistent := rb.CopyChunked(dst, 3)
+	assertEqual(t, 10, n)
+	assertEqual(t, true, consistent)
+	assertEqual(t, []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, dst[:n])
+
+	// Modifications between chunks are reported, and values are still copied
+	// newest first.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 10; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				rb.Add(i)
+			}
+		}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for consistent && time.Now().Before(deadline) {
+		n, consistent = rb.CopyChunked(dst, 1)
+		for i := 1; i < n; i++ {
+			if dst[i] >= dst[i-1] {
+				t.Fatalf("values not newest first: %v", dst[:n])
+			}
+		}
+	}
+	assertEqual(t, false, consistent)
+}
+
+func TestRingBufferWalkBatched(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](5)
+	for i := range 5 {
+		buf.Add(i)
+	}
+
+	for _, batch := range []int{0, 1, 2, 5, 10} {
+		var seen []int
+		buf.WalkBatched(batch, func(v int) error { seen = append(seen, v); return nil })
+		assertEqual(t, []int{4, 3, 2, 1, 0}, seen)
+	}
+
+	// Adds from other goroutines don't wait for the whole walk. Values they
+	// drop are skipped, as are the added values, so the walk sees 0 only if the
+	// add happens after it ends.
+	var seen []int
+	added := make(chan struct{})
+	buf.WalkBatched(2, func(v int) error {
+		seen = append(seen, v)
+		if v == 3 {
+			go func() { buf.Add(100); close(added) }()
+		}
+		return nil
+	})
+	<-added
+	if len(seen) == 5 {
+		assertEqual(t, []int{4, 3, 2, 1, 0}, seen)
+	} else {
+		assertEqual(t, []int{4, 3, 2, 1}, seen)
+	}
+
+	vals, _ := buf.Take(10)
+	assertEqual(t, []int{100, 4, 3, 2, 1}, vals)
+
+	seen = nil
+	errStop := errors.New("stop")
+	err := buf.WalkBatched(2, func(v int) error {
+		seen = append(seen, v)
+		if len(seen) == 3 {
+			return errStop
+		}
+		return nil
+	})
+	assertEqual(t, true, errors.Is(err, errStop))
+	assertEqual(t, []int{100, 4, 3}, seen)
+}
@@ -0,0 +1,196 @@
+// Command rb inspects files written by package rb: snapshots written by Save,
+// WAL directories written by OpenWAL, and shared rings written by
+// CreateSharedRing. It prints the values in a file, oldest first, optionally
+// filtered, limited to the newest values, or converted to JSON or CSV.
+//
+// Values are printed as they were encoded. Values which are valid JSON are
+// printed as JSON, values which are printable text are printed as strings, and
+// anything else is printed as hex. Trailing zero bytes, which pad short records
+// in a shared ring, are trimmed. Encrypted snapshots aren't supported.
+//
+// Examples:
+//
+//	rb dump.rbsnap                 # print every value
+//	rb -n 20 /var/lib/app/wal      # print the newest 20 values
+//	rb -grep timeout dump.rbsnap   # print values containing "timeout"
+//	rb -format csv /dev/shm/app    # convert to CSV
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/peterbourgon/rb"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "rb: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("rb", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		n      = fs.Int("n", 0, "print only the newest n values, 0 means all")
+		grep   = fs.String("grep", "", "print only values containing this string")
+		format = fs.String("format", "text", "output format: text, json, csv")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: rb [flags] <snapshot file | WAL directory | shared ring file>\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one path")
+	}
+
+	vals, err := read(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	type value struct {
+		index int
+		data  []byte
+	}
+	var selected []value
+	for i, data := range vals {
+		if *grep == "" || bytes.Contains(data, []byte(*grep)) {
+			selected = append(selected, value{i, data})
+		}
+	}
+	if *n > 0 && len(selected) > *n {
+		selected = selected[len(selected)-*n:]
+	}
+
+	w := bufio.NewWriter(stdout)
+	switch *format {
+	case "text":
+		for _, v := range selected {
+			fmt.Fprintf(w, "%d\t%s\n", v.index, render(v.data))
+		}
+	case "json":
+		enc := json.NewEncoder(w)
+		for _, v := range selected {
+			enc.Encode(struct {
+				Index int             `json:"index"`
+				Value json.RawMessage `json:"value"`
+			}{v.index, renderJSON(v.data)})
+		}
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"index", "value"})
+		for _, v := range selected {
+			cw.Write([]string{strconv.Itoa(v.index), render(v.data)})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	return w.Flush()
+}
+
+// read returns the encoded values in the file or directory at path, oldest
+// first, detecting what kind of file it is.
+func read(path string) ([][]byte, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		var vals [][]byte
+		err := rb.ReadWAL[[]byte](path, rawCodec{}, func(data []byte) error {
+			vals = append(vals, data)
+			return nil
+		})
+		return vals, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	magic := make([]byte, 8)
+	n, _ := io.ReadFull(f, magic)
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("rbsnap")):
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return rb.ReadSnapshot[[]byte](f, rawCodec{})
+	case bytes.Equal(magic, []byte("rbshm001")):
+		return readShared(path)
+	default:
+		return nil, fmt.Errorf("%s: not a snapshot, WAL directory, or shared ring", path)
+	}
+}
+
+// rawCodec returns values as they were encoded. It implements rb.Migrator, with
+// the highest possible version, and a no-op migration, so it can read values
+// from snapshots written by any codec.
+type rawCodec struct{}
+
+func (rawCodec) Encode(data []byte) ([]byte, error) { return data, nil }
+func (rawCodec) Decode(data []byte) ([]byte, error) { return data, nil }
+func (rawCodec) Version() uint64                    { return math.MaxUint64 }
+func (rawCodec) Migrate(data []byte, from uint64) ([]byte, error) {
+	return data, nil
+}
+
+// render returns the data as text, if it's printable, or as hex otherwise.
+func render(data []byte) string {
+	if printable(data) {
+		return string(data)
+	}
+	return hex.EncodeToString(data)
+}
+
+// renderJSON returns the data as-is, if it's valid JSON, or as a JSON string
+// of the rendered data otherwise.
+func renderJSON(data []byte) json.RawMessage {
+	if json.Valid(data) {
+		var buf bytes.Buffer
+		json.Compact(&buf, data)
+		return buf.Bytes()
+	}
+	s, _ := json.Marshal(render(data))
+	return s
+}
+
+func printable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
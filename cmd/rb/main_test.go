@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	type event struct {
+		Msg string `json:"msg"`
+	}
+	buf := rb.NewRingBuffer[event](4)
+	for _, msg := range []string{"ok", "timeout", "ok", "timeout again"} {
+		buf.Add(event{msg})
+	}
+	snapshot := filepath.Join(dir, "dump.rbsnap")
+	f, err := os.Create(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := buf.Save(f, rb.JSONCodec[event]{}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	wal, err := rb.OpenWAL[int64](filepath.Join(dir, "wal"), 3, rb.BinaryCodec[int64]{}, 1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wal.Add(1)
+	wal.Close()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{snapshot},
+			"0\t{\"msg\":\"ok\"}\n1\t{\"msg\":\"timeout\"}\n2\t{\"msg\":\"ok\"}\n3\t{\"msg\":\"timeout again\"}\n",
+		},
+		{
+			[]string{"-grep", "timeout", "-n", "1", "-format", "json", snapshot},
+			"{\"index\":3,\"value\":{\"msg\":\"timeout again\"}}\n",
+		},
+		{
+			[]string{"-n", "1", "-format", "csv", snapshot},
+			"index,value\n3,\"{\"\"msg\"\":\"\"timeout again\"\"}\"\n",
+		},
+		{
+			[]string{filepath.Join(dir, "wal")},
+			"0\t0100000000000000\n",
+		},
+	} {
+		var stdout, stderr bytes.Buffer
+		if err := run(tc.args, &stdout, &stderr); err != nil {
+			t.Errorf("%v: %v", tc.args, err)
+			continue
+		}
+		if have := stdout.String(); have != tc.want {
+			t.Errorf("%v: want %q, have %q", tc.args, tc.want, have)
+		}
+	}
+
+	if err := run([]string{dir}, &bytes.Buffer{}, &bytes.Buffer{}); err != nil {
+		t.Errorf("empty WAL directory: %v", err)
+	}
+	if err := run([]string{"-format", "xml", snapshot}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("unknown format: want error")
+	}
+}
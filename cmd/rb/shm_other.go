@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "errors"
+
+// readShared isn't supported, because shared rings are only supported on unix.
+func readShared(path string) ([][]byte, error) {
+	return nil, errors.New("shared rings are only supported on unix")
+}
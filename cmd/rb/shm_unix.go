@@ -0,0 +1,28 @@
+//go:build unix
+
+package main
+
+import (
+	"bytes"
+	"math"
+	"slices"
+
+	"github.com/peterbourgon/rb"
+)
+
+// readShared returns the records in the shared ring at path, oldest first,
+// with trailing zero bytes trimmed.
+func readShared(path string) ([][]byte, error) {
+	r, err := rb.OpenSharedRing(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	records := r.Take(math.MaxInt)
+	slices.Reverse(records)
+	for i, record := range records {
+		records[i] = bytes.TrimRight(record, "\x00")
+	}
+	return records, nil
+}
@@ -0,0 +1,96 @@
+package rb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Codec converts values of type T to and from bytes. It's used by features
+// which store or transmit values outside of memory, e.g. persistence,
+// replication, and compression, so that any of them can be used with any value
+// type, given a codec for that type.
+type Codec[T any] interface {
+	Encode(val T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec encodes values as JSON, via encoding/json.
+type JSONCodec[T any] struct{}
+
+var _ Codec[int] = JSONCodec[int]{}
+
+// Encode implements Codec.
+func (JSONCodec[T]) Encode(val T) ([]byte, error) {
+	return json.Marshal(val)
+}
+
+// Decode implements Codec.
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var val T
+	err := json.Unmarshal(data, &val)
+	return val, err
+}
+
+// GobCodec encodes values via encoding/gob. Each value is encoded separately,
+// including its type information, so values can be decoded independently.
+type GobCodec[T any] struct{}
+
+var _ Codec[int] = GobCodec[int]{}
+
+// Encode implements Codec.
+func (GobCodec[T]) Encode(val T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(val); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode implements Codec.
+func (GobCodec[T]) Decode(data []byte) (T, error) {
+	var val T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&val)
+	return val, err
+}
+
+// BinaryCodec encodes fixed-size values, e.g. numbers, or structs and arrays of
+// numbers, via encoding/binary. That's compact, and fast, but values of other
+// types, e.g. strings or slices, return an error. If Order is nil, it defaults
+// to little endian.
+type BinaryCodec[T any] struct {
+	Order binary.ByteOrder
+}
+
+var _ Codec[int64] = BinaryCodec[int64]{}
+
+// Encode implements Codec.
+func (c BinaryCodec[T]) Encode(val T) ([]byte, error) {
+	if binary.Size(val) < 0 {
+		return nil, fmt.Errorf("binary codec: %T isn't a fixed-size type", val)
+	}
+	return binary.Append(nil, c.order(), val)
+}
+
+// Decode implements Codec.
+func (c BinaryCodec[T]) Decode(data []byte) (T, error) {
+	var val T
+	size := binary.Size(val)
+	if size < 0 {
+		return val, fmt.Errorf("binary codec: %T isn't a fixed-size type", val)
+	}
+	if len(data) != size {
+		return val, fmt.Errorf("binary codec: %T has size %d, but data has size %d", val, size, len(data))
+	}
+	_, err := binary.Decode(data, c.order(), &val)
+	return val, err
+}
+
+func (c BinaryCodec[T]) order() binary.ByteOrder {
+	if c.Order == nil {
+		return binary.LittleEndian
+	}
+	return c.Order
+}
@@ -0,0 +1,51 @@
+package rb_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+type point struct {
+	X, Y int32
+}
+
+func TestCodecs(t *testing.T) {
+	t.Parallel()
+
+	testCodec(t, "JSON", rb.JSONCodec[point]{}, point{1, -2})
+	testCodec(t, "gob", rb.GobCodec[point]{}, point{3, -4})
+	testCodec(t, "binary", rb.BinaryCodec[point]{}, point{5, -6})
+	testCodec(t, "binary big endian", rb.BinaryCodec[point]{Order: binary.BigEndian}, point{7, -8})
+	testCodec(t, "JSON string", rb.JSONCodec[string]{}, "hello")
+	testCodec(t, "gob string", rb.GobCodec[string]{}, "world")
+
+	// Binary only supports fixed-size types.
+	if _, err := (rb.BinaryCodec[string]{}).Encode("nope"); err == nil {
+		t.Errorf("binary codec: want error for string")
+	}
+	if _, err := (rb.BinaryCodec[point]{}).Decode([]byte{1, 2, 3}); err == nil {
+		t.Errorf("binary codec: want error for short data")
+	}
+
+	// Binary is compact.
+	data, _ := rb.BinaryCodec[point]{}.Encode(point{})
+	assertEqual(t, 8, len(data))
+}
+
+func testCodec[T any](t *testing.T, name string, codec rb.Codec[T], val T) {
+	t.Helper()
+
+	data, err := codec.Encode(val)
+	if err != nil {
+		t.Fatalf("%s: Encode: %v", name, err)
+	}
+
+	have, err := codec.Decode(data)
+	if err != nil {
+		t.Fatalf("%s: Decode: %v", name, err)
+	}
+
+	assertEqual(t, val, have)
+}
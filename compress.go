@@ -0,0 +1,201 @@
+package rb
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"sync"
+)
+
+// Compressor compresses and decompresses byte slices.
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// FlateCompressor is a Compressor using DEFLATE, via compress/flate, at the
+// given level, e.g. flate.BestSpeed. The zero value uses the default level.
+type FlateCompressor struct {
+	Level int
+}
+
+var _ Compressor = FlateCompressor{}
+
+// Compress implements Compressor.
+func (c FlateCompressor) Compress(data []byte) ([]byte, error) {
+	level := c.Level
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress implements Compressor.
+func (c FlateCompressor) Decompress(data []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// CompressedRingBuffer is a Buffer which stores values encoded by a codec, and
+// compressed by a compressor, and decodes them when they're read. That trades
+// CPU for memory, which is worthwhile for deep histories of large, compressible
+// values, e.g. text payloads. See SetByteLimit to bound memory use directly.
+//
+// Values are encoded and compressed before the lock is taken, and decoded after
+// it's released, except during Walk, which decodes each value under the lock.
+//
+// Add can't return an error, so if a value can't be encoded or compressed, it's
+// not stored, and the error is available via Err. If a stored value can't be
+// decoded, Walk and Take return the error, and other methods return a zero
+// value in its place.
+type CompressedRingBuffer[T any] struct {
+	rb         *RingBuffer[[]byte]
+	codec      Codec[T]
+	compressor Compressor
+
+	mtx sync.Mutex
+	err error
+}
+
+var _ Buffer[int] = (*CompressedRingBuffer[int])(nil)
+
+// NewCompressedRingBuffer returns an empty compressed ring buffer of size sz.
+func NewCompressedRingBuffer[T any](sz int, codec Codec[T], compressor Compressor) *CompressedRingBuffer[T] {
+	return &CompressedRingBuffer[T]{
+		rb:         NewRingBuffer[[]byte](sz),
+		codec:      codec,
+		compressor: compressor,
+	}
+}
+
+// SetByteLimit bounds the total compressed size of all values, in addition to
+// the count of values, as with SetCostLimit. Values dropped as a result are
+// returned, newest first. If limit <= 0, the limit is removed.
+func (b *CompressedRingBuffer[T]) SetByteLimit(limit int) (dropped []T) {
+	if limit <= 0 {
+		b.rb.SetCostLimit(nil, 0)
+		return nil
+	}
+
+	return b.decodeAll(b.rb.SetCostLimit(func(data []byte) int { return len(data) }, limit))
+}
+
+// Err returns the most recent error from encoding or compressing a value in
+// Add, if any.
+func (b *CompressedRingBuffer[T]) Err() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.err
+}
+
+// Stats returns statistics for the underlying ring buffer. SizeBytes includes
+// the compressed size of the values.
+func (b *CompressedRingBuffer[T]) Stats() Stats {
+	stats := b.rb.Stats()
+	stats.SizeBytes = b.rb.SizeBytes(func(data []byte) int { return len(data) })
+	return stats
+}
+
+// Add implements Buffer.
+func (b *CompressedRingBuffer[T]) Add(val T) (dropped T, ok bool) {
+	data, err := b.encode(val)
+	if err != nil {
+		b.mtx.Lock()
+		b.err = err
+		b.mtx.Unlock()
+		return dropped, false
+	}
+
+	d, ok := b.rb.Add(data)
+	if !ok {
+		return dropped, false
+	}
+
+	dropped, _ = b.decode(d)
+	return dropped, true
+}
+
+// Walk implements Buffer.
+func (b *CompressedRingBuffer[T]) Walk(fn func(T) error) error {
+	return b.rb.Walk(func(data []byte) error {
+		val, err := b.decode(data)
+		if err != nil {
+			return err
+		}
+		return fn(val)
+	})
+}
+
+// Take implements Buffer.
+func (b *CompressedRingBuffer[T]) Take(n int) ([]T, error) {
+	datas, err := b.rb.Take(n)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]T, len(datas))
+	for i, data := range datas {
+		if vals[i], err = b.decode(data); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// Overview implements Buffer.
+func (b *CompressedRingBuffer[T]) Overview() (newest, oldest T, count int) {
+	n, o, count := b.rb.Overview()
+	if count == 0 {
+		return newest, oldest, 0
+	}
+	newest, _ = b.decode(n)
+	oldest, _ = b.decode(o)
+	return newest, oldest, count
+}
+
+// Resize implements Buffer.
+func (b *CompressedRingBuffer[T]) Resize(sz int) (dropped []T) {
+	return b.decodeAll(b.rb.Resize(sz))
+}
+
+func (b *CompressedRingBuffer[T]) encode(val T) ([]byte, error) {
+	data, err := b.codec.Encode(val)
+	if err != nil {
+		return nil, err
+	}
+	return b.compressor.Compress(data)
+}
+
+func (b *CompressedRingBuffer[T]) decode(data []byte) (T, error) {
+	data, err := b.compressor.Decompress(data)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return b.codec.Decode(data)
+}
+
+func (b *CompressedRingBuffer[T]) decodeAll(datas [][]byte) []T {
+	if datas == nil {
+		return nil
+	}
+	vals := make([]T, len(datas))
+	for i, data := range datas {
+		vals[i], _ = b.decode(data)
+	}
+	return vals
+}
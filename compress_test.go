@@ -0,0 +1,60 @@
+package rb_test
+
+import (
+	"compress/flate"
+	"strings"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestCompressedRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	b := rb.NewCompressedRingBuffer[string](3, rb.JSONCodec[string]{}, rb.FlateCompressor{Level: flate.BestSpeed})
+
+	payload := func(c string) string { return strings.Repeat(c, 1000) }
+	for _, c := range []string{"a", "b", "c"} {
+		b.Add(payload(c))
+	}
+	dropped, ok := b.Add(payload("d"))
+	assertEqual(t, true, ok)
+	assertEqual(t, payload("a"), dropped)
+
+	vals, err := b.Take(10)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []string{payload("d"), payload("c"), payload("b")}, vals)
+
+	newest, oldest, count := b.Overview()
+	assertEqual(t, payload("d"), newest)
+	assertEqual(t, payload("b"), oldest)
+	assertEqual(t, 3, count)
+
+	var walked []string
+	b.Walk(func(s string) error { walked = append(walked, s[:1]); return nil })
+	assertEqual(t, []string{"d", "c", "b"}, walked)
+
+	// Values are stored compressed.
+	if stats := b.Stats(); stats.SizeBytes > 3*100 {
+		t.Errorf("SizeBytes: want compressed size, have %d", stats.SizeBytes)
+	}
+
+	// A byte limit bounds the compressed size.
+	dropped2 := b.SetByteLimit(1)
+	assertEqual(t, []string{payload("c"), payload("b")}, dropped2)
+	vals, _ = b.Take(10)
+	assertEqual(t, []string{payload("d")}, vals)
+	assertEqual(t, error(nil), b.Err())
+}
+
+func TestCompressedRingBufferErr(t *testing.T) {
+	t.Parallel()
+
+	b := rb.NewCompressedRingBuffer[string](3, rb.BinaryCodec[string]{}, rb.FlateCompressor{})
+	b.Add("not fixed size")
+	_, _, count := b.Overview()
+	assertEqual(t, 0, count)
+	if b.Err() == nil {
+		t.Errorf("want error")
+	}
+}
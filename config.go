@@ -0,0 +1,160 @@
+package rb
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the configuration of a set of ring buffers, which can be applied at
+// runtime via Configure, e.g. when a config file is reloaded. Zero values mean
+// the corresponding feature is disabled, except for Size, where it means the
+// size is unchanged.
+type Config struct {
+	Size       int            // capacity of each ring buffer
+	Sizes      map[string]int // capacity of specific categories, overriding Size
+	Budget     int            // max total capacity across categories, see SetBudget
+	TTL        time.Duration  // default TTL for values, see RingBuffer.SetTTL
+	EmptyTTL   time.Duration  // remove empty categories, see RemoveEmptyAfter
+	SampleRate float64        // fraction of values kept, see RingBuffer.SetSampleRate
+}
+
+// Configure applies the config to every ring buffer in the set, and to ring
+// buffers subsequently created, and returns any values dropped as a result, by
+// category. The whole config is applied under the lock of the set, so other
+// operations on the set observe either the old config or the new config, and
+// never a mix of the two.
+func (rbs *RingBuffers[T]) Configure(cfg Config) (dropped map[string][]T) {
+	rbs.mtx.Lock()
+	defer rbs.mtx.Unlock()
+
+	if cfg.Size > 0 {
+		rbs.sz = cfg.Size
+	}
+	rbs.sizes = maps.Clone(cfg.Sizes)
+	for name := range cfg.Sizes {
+		delete(rbs.resized, name)
+	}
+	rbs.budget = max(0, cfg.Budget)
+	rbs.ttl = cfg.TTL
+	rbs.sampleRate = cfg.SampleRate
+	if cfg.EmptyTTL != rbs.emptyTTL {
+		rbs.emptyTTL = max(0, cfg.EmptyTTL)
+		rbs.emptySince = nil
+		rbs.lastSweep = rbs.clock()
+	}
+
+	dropped = map[string][]T{}
+	for name, rb := range rbs.bufs {
+		if vals := rb.Resize(rbs.sizeFor(name)); len(vals) > 0 {
+			dropped[name] = vals
+		}
+		rb.SetTTL(rbs.ttl)
+		rb.SetSampleRate(rbs.sampleRate)
+	}
+	for name, vals := range rbs.enforceBudget() {
+		if len(vals) > 0 {
+			dropped[name] = append(dropped[name], vals...)
+		}
+	}
+
+	return dropped
+}
+
+// sizeFor returns the configured size for the category, or the size it was
+// last given by ResizeEach. It assumes the lock is held.
+func (rbs *RingBuffers[T]) sizeFor(category string) int {
+	if sz, ok := rbs.resized[category]; ok {
+		return sz
+	}
+	if sz, ok := rbs.sizes[category]; ok && sz > 0 {
+		return sz
+	}
+	return rbs.sz
+}
+
+// newRingBuffer returns a new ring buffer of size sz, with the configured TTL
+// and sample rate. It assumes the lock is held.
+func (rbs *RingBuffers[T]) newRingBuffer(sz int) *RingBuffer[T] {
+	rb := NewRingBuffer[T](sz)
+	if rbs.ttl > 0 {
+		rb.SetTTL(rbs.ttl)
+	}
+	if rbs.sampleRate > 0 {
+		rb.SetSampleRate(rbs.sampleRate)
+	}
+	return rb
+}
+
+// RegisterFlags registers flags for each field of the config in the flag set,
+// named with the prefix "rb.", e.g. -rb.size. The current values of the config
+// are the defaults, so call ParseEnv first, if environment variables should
+// take effect, and be overridden by flags.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.Size, "rb.size", c.Size, "capacity of each ring buffer")
+	fs.Var((*sizesFlag)(&c.Sizes), "rb.sizes", "capacity of specific categories, as category=size,...")
+	fs.IntVar(&c.Budget, "rb.budget", c.Budget, "max total capacity across categories, 0 for unlimited")
+	fs.DurationVar(&c.TTL, "rb.ttl", c.TTL, "retention duration of values, 0 for unlimited")
+	fs.DurationVar(&c.EmptyTTL, "rb.empty-ttl", c.EmptyTTL, "remove categories empty for this long, 0 to keep them")
+	fs.Float64Var(&c.SampleRate, "rb.sample-rate", c.SampleRate, "fraction of values kept, 0 or 1 to keep all")
+}
+
+// ParseEnv sets fields of the config from environment variables, named after
+// the flags from RegisterFlags, in upper case, with punctuation replaced by
+// underscores, e.g. RB_SIZE and RB_EMPTY_TTL. Unset variables leave fields
+// unchanged, and invalid values are an error.
+func (c *Config) ParseEnv() error {
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	c.RegisterFlags(fs)
+
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(f.Name))
+		if val, ok := os.LookupEnv(name); ok {
+			if err := fs.Set(f.Name, val); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// sizesFlag is a flag.Value for Config.Sizes.
+type sizesFlag map[string]int
+
+func (f *sizesFlag) String() string {
+	if f == nil || len(*f) == 0 {
+		return ""
+	}
+	var pairs []string
+	for _, category := range slices.Sorted(maps.Keys(*f)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", category, (*f)[category]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f *sizesFlag) Set(s string) error {
+	sizes := map[string]int{}
+	for pair := range strings.SplitSeq(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		category, size, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("%q: want category=size", pair)
+		}
+		n, err := strconv.Atoi(size)
+		if err != nil {
+			return fmt.Errorf("%q: %w", pair, err)
+		}
+		sizes[category] = n
+	}
+	*f = sizes
+	return nil
+}
@@ -0,0 +1,98 @@
+package rb_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBuffersConfigure(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int](5)
+	for _, v := range []int{1, 2, 3, 4, 5} {
+		rbs.GetOrCreate("foo").Add(v)
+		rbs.GetOrCreate("bar").Add(v)
+	}
+
+	dropped := rbs.Configure(rb.Config{
+		Size:  3,
+		Sizes: map[string]int{"bar": 4, "baz": 2},
+		TTL:   time.Hour,
+	})
+	assertEqual(t, map[string][]int{"foo": {2, 1}, "bar": {1}}, dropped)
+
+	capacity := func(category string) int { return rbs.GetOrCreate(category).Stats().Capacity }
+	assertEqual(t, 3, capacity("foo"))
+	assertEqual(t, 4, capacity("bar"))
+	assertEqual(t, 2, capacity("baz")) // new categories use the config, too
+	assertEqual(t, 3, capacity("qux"))
+
+	// A reload replaces the previous config.
+	dropped = rbs.Configure(rb.Config{Size: 4, Budget: 12})
+	assertEqual(t, map[string][]int{"bar": {2}}, dropped)
+	for _, category := range []string{"foo", "bar", "baz", "qux"} {
+		assertEqual(t, 3, capacity(category)) // 4 each exceeds the budget
+	}
+}
+
+func TestRingBuffersConfigureAfterResizeEach(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int](5)
+	capacity := func(category string) int { return rbs.GetOrCreate(category).Stats().Capacity }
+
+	// Sizes from ResizeEach survive a reload.
+	rbs.ResizeEach(map[string]int{"foo": 2, "bar": 7})
+	rbs.Configure(rb.Config{Size: 3})
+	assertEqual(t, 2, capacity("foo"))
+	assertEqual(t, 7, capacity("bar"))
+	assertEqual(t, 3, capacity("baz"))
+
+	// Unless the config sizes the same categories.
+	rbs.Configure(rb.Config{Size: 3, Sizes: map[string]int{"bar": 4}})
+	assertEqual(t, 2, capacity("foo"))
+	assertEqual(t, 4, capacity("bar"))
+
+	rbs.Configure(rb.Config{Size: 3})
+	assertEqual(t, 2, capacity("foo"))
+	assertEqual(t, 3, capacity("bar"))
+}
+
+func TestConfigRegisterFlags(t *testing.T) {
+	t.Parallel()
+
+	cfg := rb.Config{Size: 100}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+
+	err := fs.Parse([]string{"-rb.sizes", "http=10,grpc=20", "-rb.ttl", "5m", "-rb.sample-rate", "0.1"})
+	assertEqual(t, error(nil), err)
+	assertEqual(t, rb.Config{
+		Size:       100,
+		Sizes:      map[string]int{"http": 10, "grpc": 20},
+		TTL:        5 * time.Minute,
+		SampleRate: 0.1,
+	}, cfg)
+	assertEqual(t, "grpc=20,http=10", fs.Lookup("rb.sizes").Value.String())
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cfg.RegisterFlags(fs)
+	assertEqual(t, true, fs.Parse([]string{"-rb.sizes", "http"}) != nil)
+}
+
+func TestConfigParseEnv(t *testing.T) {
+	t.Setenv("RB_SIZE", "50")
+	t.Setenv("RB_EMPTY_TTL", "1h")
+
+	cfg := rb.Config{Budget: 1000}
+	assertEqual(t, error(nil), cfg.ParseEnv())
+	assertEqual(t, rb.Config{Size: 50, Budget: 1000, EmptyTTL: time.Hour}, cfg)
+
+	t.Setenv("RB_BUDGET", "lots")
+	assertEqual(t, true, cfg.ParseEnv() != nil)
+}
@@ -0,0 +1,170 @@
+package rb
+
+import (
+	"fmt"
+	"sync"
+)
+
+// OffsetStore persists the committed offsets of named consumers, see
+// ConsumerGroup. Implementations must be safe for concurrent use.
+type OffsetStore interface {
+	// LoadOffset returns the committed offset of the consumer, or false if it
+	// has never committed.
+	LoadOffset(consumer string) (offset uint64, ok bool, err error)
+
+	// CommitOffset sets the committed offset of the consumer.
+	CommitOffset(consumer string, offset uint64) error
+}
+
+// ConsumerGroup tracks the progress of named consumers through a ring buffer,
+// for at-least-once delivery to a slower downstream. Each consumer fetches
+// values with Fetch, and acknowledges them with Ack once they're processed.
+// Values which are fetched but not acknowledged are fetched again, e.g. after
+// the consumer restarts.
+//
+// A consumer's offset is the sequence number of the next value it hasn't
+// acknowledged. Offsets are committed to the store on every Ack, and loaded
+// from the store when a consumer first fetches. Sequence numbers are assigned
+// by the ring buffer, and start at 0, so committed offsets are only meaningful
+// for as long as the ring buffer that assigned them. A committed offset which
+// is ahead of the ring buffer, e.g. because it was committed before a restart,
+// is reset to the oldest value in the ring buffer, and reported via OnReset.
+type ConsumerGroup[T any] struct {
+	// OnReset, if non-nil, is called when a consumer's committed offset is
+	// ahead of the ring buffer, with the committed offset and the offset it's
+	// reset to. Values the consumer hadn't acknowledged before the ring buffer
+	// was replaced are lost.
+	OnReset func(consumer string, from, to uint64)
+
+	rb    *RingBuffer[T]
+	store OffsetStore
+
+	mtx     sync.Mutex
+	offsets map[string]uint64 // cache of committed offsets
+}
+
+// NewConsumerGroup returns a consumer group for the ring buffer, with offsets
+// committed to the given store. If store is nil, offsets are kept in memory.
+func NewConsumerGroup[T any](rb *RingBuffer[T], store OffsetStore) *ConsumerGroup[T] {
+	if store == nil {
+		store = &memoryOffsetStore{offsets: map[string]uint64{}}
+	}
+	return &ConsumerGroup[T]{
+		rb:      rb,
+		store:   store,
+		offsets: map[string]uint64{},
+	}
+}
+
+// Fetch returns up to n of the oldest values which the consumer hasn't
+// acknowledged, as entries, oldest first. Values which were overwritten before
+// they could be fetched are skipped, which is visible as a gap in the sequence
+// numbers. A new consumer starts with the oldest value in the ring buffer.
+func (cg *ConsumerGroup[T]) Fetch(consumer string, n int) ([]Entry[T], error) {
+	offset, err := cg.offset(consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	cg.rb.lock()
+	committed := offset
+	if offset > cg.rb.seq {
+		offset = cg.rb.seq - uint64(cg.rb.len)
+	}
+	entries, _ := cg.rb.entriesSince(offset)
+	cg.rb.unlock()
+
+	if offset != committed {
+		if err := cg.reset(consumer, committed, offset); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(entries) > n {
+		entries = entries[:max(0, n)]
+	}
+	return entries, nil
+}
+
+// Ack acknowledges the value with the given sequence number, and all values
+// before it, and commits the consumer's offset to the store. Acknowledging a
+// value which was already acknowledged is a no-op.
+func (cg *ConsumerGroup[T]) Ack(consumer string, seq uint64) error {
+	if _, err := cg.offset(consumer); err != nil {
+		return err
+	}
+
+	cg.mtx.Lock()
+	defer cg.mtx.Unlock()
+
+	if seq < cg.offsets[consumer] {
+		return nil
+	}
+	if err := cg.store.CommitOffset(consumer, seq+1); err != nil {
+		return fmt.Errorf("commit offset: %w", err)
+	}
+	cg.offsets[consumer] = seq + 1
+
+	return nil
+}
+
+// offset returns the committed offset of the consumer, loading it from the
+// store if it isn't cached.
+func (cg *ConsumerGroup[T]) offset(consumer string) (uint64, error) {
+	cg.mtx.Lock()
+	defer cg.mtx.Unlock()
+
+	if offset, ok := cg.offsets[consumer]; ok {
+		return offset, nil
+	}
+
+	offset, _, err := cg.store.LoadOffset(consumer)
+	if err != nil {
+		return 0, fmt.Errorf("load offset: %w", err)
+	}
+	cg.offsets[consumer] = offset
+	return offset, nil
+}
+
+// reset commits the new offset of a consumer whose committed offset is ahead of
+// the ring buffer, and reports it via OnReset.
+func (cg *ConsumerGroup[T]) reset(consumer string, from, to uint64) error {
+	cg.mtx.Lock()
+	if cg.offsets[consumer] != from {
+		cg.mtx.Unlock()
+		return nil // acknowledged concurrently
+	}
+	if err := cg.store.CommitOffset(consumer, to); err != nil {
+		cg.mtx.Unlock()
+		return fmt.Errorf("commit offset: %w", err)
+	}
+	cg.offsets[consumer] = to
+	cg.mtx.Unlock()
+
+	if cg.OnReset != nil {
+		cg.OnReset(consumer, from, to)
+	}
+	return nil
+}
+
+// memoryOffsetStore is the default OffsetStore.
+type memoryOffsetStore struct {
+	mtx     sync.Mutex
+	offsets map[string]uint64
+}
+
+func (s *memoryOffsetStore) LoadOffset(consumer string) (uint64, bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	offset, ok := s.offsets[consumer]
+	return offset, ok, nil
+}
+
+func (s *memoryOffsetStore) CommitOffset(consumer string, offset uint64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.offsets[consumer] = offset
+	return nil
+}
@@ -0,0 +1,98 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+type offsetStore map[string]uint64
+
+func (s offsetStore) LoadOffset(consumer string) (uint64, bool, error) {
+	offset, ok := s[consumer]
+	return offset, ok, nil
+}
+
+func (s offsetStore) CommitOffset(consumer string, offset uint64) error {
+	s[consumer] = offset
+	return nil
+}
+
+func TestConsumerGroup(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[string](4)
+	for _, v := range []string{"a", "b", "c"} {
+		buf.Add(v)
+	}
+
+	store := offsetStore{}
+	cg := rb.NewConsumerGroup(buf, store)
+
+	entries, err := cg.Fetch("slow", 2)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []rb.Entry[string]{{Seq: 0, Value: "a"}, {Seq: 1, Value: "b"}}, entries)
+
+	// Unacknowledged values are fetched again.
+	entries, _ = cg.Fetch("slow", 2)
+	assertEqual(t, []rb.Entry[string]{{Seq: 0, Value: "a"}, {Seq: 1, Value: "b"}}, entries)
+
+	assertEqual(t, error(nil), cg.Ack("slow", 1))
+	assertEqual(t, uint64(2), store["slow"])
+	entries, _ = cg.Fetch("slow", 10)
+	assertEqual(t, []rb.Entry[string]{{Seq: 2, Value: "c"}}, entries)
+
+	// Consumers are independent.
+	entries, _ = cg.Fetch("other", 1)
+	assertEqual(t, []rb.Entry[string]{{Seq: 0, Value: "a"}}, entries)
+
+	// Stale acks are ignored.
+	assertEqual(t, error(nil), cg.Ack("slow", 0))
+	assertEqual(t, uint64(2), store["slow"])
+
+	// Overwritten values are skipped.
+	for _, v := range []string{"d", "e", "f", "g"} {
+		buf.Add(v)
+	}
+	entries, _ = cg.Fetch("slow", 1)
+	assertEqual(t, []rb.Entry[string]{{Seq: 3, Value: "d"}}, entries)
+
+	// A new consumer group resumes from the committed offsets.
+	cg = rb.NewConsumerGroup(buf, store)
+	entries, _ = cg.Fetch("slow", 1)
+	assertEqual(t, []rb.Entry[string]{{Seq: 3, Value: "d"}}, entries)
+}
+
+func TestConsumerGroupReset(t *testing.T) {
+	t.Parallel()
+
+	store := offsetStore{"slow": 5, "fast": 1}
+
+	// After a restart, the new ring buffer's sequence numbers start again at
+	// 0, behind the offset committed for the previous one.
+	buf := rb.NewRingBuffer[string](4)
+	for _, v := range []string{"a", "b", "c"} {
+		buf.Add(v)
+	}
+
+	type reset struct {
+		Consumer string
+		From, To uint64
+	}
+	var resets []reset
+	cg := rb.NewConsumerGroup(buf, store)
+	cg.OnReset = func(consumer string, from, to uint64) {
+		resets = append(resets, reset{consumer, from, to})
+	}
+
+	entries, err := cg.Fetch("slow", 2)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []rb.Entry[string]{{Seq: 0, Value: "a"}, {Seq: 1, Value: "b"}}, entries)
+	assertEqual(t, uint64(0), store["slow"])
+	assertEqual(t, []reset{{"slow", 5, 0}}, resets)
+
+	// Offsets within the ring buffer aren't reset.
+	entries, _ = cg.Fetch("fast", 2)
+	assertEqual(t, []rb.Entry[string]{{Seq: 1, Value: "b"}, {Seq: 2, Value: "c"}}, entries)
+	assertEqual(t, 1, len(resets))
+}
@@ -0,0 +1,26 @@
+package rb
+
+import (
+	"time"
+)
+
+// TrackContention enables or disables tracking of time spent waiting to acquire
+// the ring buffer's lock, which is reported via Stats. Uncontended acquisitions
+// aren't timed, so the overhead is only paid when the lock is already held.
+func (rb *RingBuffer[T]) TrackContention(enabled bool) {
+	rb.contention.Store(enabled)
+}
+
+// lockSlow acquires the lock after a failed TryLock, timing the wait if
+// contention tracking is enabled.
+func (rb *RingBuffer[T]) lockSlow() {
+	if !rb.contention.Load() {
+		rb.mtx.Lock()
+		return
+	}
+
+	begin := time.Now()
+	rb.mtx.Lock()
+	rb.lockWait += time.Since(begin)
+	rb.contended += 1
+}
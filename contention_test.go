@@ -0,0 +1,41 @@
+package rb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestTrackContention(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](3)
+	rb.Add(1)
+
+	contend := func() {
+		walking, release, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+		go func() {
+			rb.Walk(func(int) error { close(walking); <-release; return nil })
+			close(done)
+		}()
+		<-walking
+		go func() { time.Sleep(10 * time.Millisecond); close(release) }()
+		rb.Overview() // blocks until the walk completes
+		<-done
+	}
+
+	// Disabled by default.
+	contend()
+	stats := rb.Stats()
+	assertEqual(t, 0, stats.Contended)
+	assertEqual(t, time.Duration(0), stats.LockWait)
+
+	rb.TrackContention(true)
+	contend()
+	stats = rb.Stats()
+	assertEqual(t, 1, stats.Contended)
+	if stats.LockWait < 5*time.Millisecond {
+		t.Errorf("LockWait: want >= 5ms, have %s", stats.LockWait)
+	}
+}
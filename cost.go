@@ -0,0 +1,62 @@
+package rb
+
+import (
+	"slices"
+)
+
+// SetCostLimit bounds the total cost of all values in the ring buffer, in
+// addition to the count of values. The cost of each value is computed by the
+// given function, which must always return the same cost for the same value,
+// e.g. its size in bytes. Each add evicts as many of the oldest values as
+// necessary to keep the total cost at or below the limit, skipping pinned
+// values, though the value being added is always kept. Any values dropped as a result of setting the
+// cost limit are returned, newest first. If cost is nil, the limit is removed.
+func (rb *RingBuffer[T]) SetCostLimit(cost func(T) int, limit int) (dropped []T) {
+	rb.lock()
+	defer rb.unlock()
+
+	rb.cost = cost
+	rb.setHooks()
+	rb.costLimit = limit
+	rb.recost()
+
+	if rb.cost == nil {
+		return nil
+	}
+
+	dropped = []T{}
+	for rb.costTotal > rb.costLimit && rb.len > 1 {
+		dropped = append(dropped, rb.dropForCost())
+	}
+	if len(dropped) > 0 {
+		rb.changed()
+	}
+
+	slices.Reverse(dropped)
+	return dropped
+}
+
+// dropForCost removes and returns the oldest value which isn't pinned, other
+// than the newest value, or the oldest value, if they're all pinned. It assumes
+// the lock is held, and the ring buffer has more than one value.
+func (rb *RingBuffer[T]) dropForCost() T {
+	if victim := rb.evictable(1); victim < rb.len-1 {
+		val := rb.remove(victim)
+		rb.costTotal -= rb.cost(val)
+		return val
+	}
+	return rb.dropOldest()
+}
+
+// recost recomputes the total cost of all values. It assumes the lock is held.
+func (rb *RingBuffer[T]) recost() {
+	rb.costTotal = 0
+
+	if rb.cost == nil {
+		return
+	}
+
+	for i := range rb.len {
+		rb.costTotal += rb.cost(rb.buf[rb.index(i)])
+	}
+}
@@ -0,0 +1,64 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestCostLimit(t *testing.T) {
+	t.Parallel()
+
+	size := func(s string) int { return len(s) }
+
+	rb := rb.NewRingBuffer[string](10)
+	rb.Add("aaaa")
+	rb.Add("bb")
+	rb.Add("cccc")
+
+	dropped := rb.SetCostLimit(size, 7)
+	assertEqual(t, []string{"aaaa"}, dropped)
+	assertEqual(t, 6, rb.Stats().Cost)
+
+	// A big value evicts multiple old values, and returns the newest of them.
+	d, ok := rb.Add("ddddddd")
+	assertEqual(t, "cccc", d)
+	assertEqual(t, true, ok)
+	assertEqual(t, 7, rb.Stats().Cost)
+
+	// A value over the limit is still kept.
+	rb.Add("eeeeeeeeee")
+	vals, _ := rb.Take(10)
+	assertEqual(t, []string{"eeeeeeeeee"}, vals)
+
+	// Small values are limited by count.
+	rb.SetCostLimit(size, 100)
+	for range 20 {
+		rb.Add("f")
+	}
+	assertEqual(t, rb.Stats().Count, 10)
+	assertEqual(t, rb.Stats().Cost, 10)
+
+	rb.Clear()
+	assertEqual(t, rb.Stats().Cost, 0)
+
+	// Removing the limit resets the cost.
+	rb.Add("g")
+	assertEqual(t, []string(nil), rb.SetCostLimit(nil, 0))
+	assertEqual(t, rb.Stats().Cost, 0)
+}
+
+func TestCostLimitPinned(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](10)
+	rb.Pin(func(i int) bool { return i < 0 }, 0.5)
+	for _, i := range []int{-1, 1, 2, 3} {
+		rb.Add(i)
+	}
+
+	// Setting a limit skips pinned values, like adds do.
+	assertEqual(t, []int{2, 1}, rb.SetCostLimit(func(int) int { return 1 }, 2))
+	vals, _ := rb.Take(10)
+	assertEqual(t, []int{3, -1}, vals)
+}
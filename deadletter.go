@@ -0,0 +1,62 @@
+package rb
+
+import (
+	"time"
+)
+
+// Rejection is a value which was rejected by a ring buffer, and the time it was
+// rejected, see SetDeadLetters.
+type Rejection[T any] struct {
+	Value T
+	Time  time.Time
+}
+
+// SetDeadLetters records up to the sz most recently rejected values, i.e.
+// values rejected by the eviction policy, e.g. RejectNew, or by EnforceOrder,
+// so capacity incidents can be diagnosed after the fact. Previously recorded
+// values are discarded. If sz <= 0, rejected values aren't recorded.
+func (rb *RingBuffer[T]) SetDeadLetters(sz int) {
+	rb.lock()
+	defer rb.unlock()
+
+	rb.deadLetters = nil
+	if sz > 0 {
+		rb.deadLetters = &deadLetters[T]{buf: make([]Rejection[T], sz)}
+	}
+}
+
+// DeadLetters returns the recorded rejected values, newest first.
+func (rb *RingBuffer[T]) DeadLetters() []Rejection[T] {
+	rb.lock()
+	defer rb.unlock()
+
+	dl := rb.deadLetters
+	if dl == nil {
+		return []Rejection[T]{}
+	}
+
+	rejections := make([]Rejection[T], dl.len)
+	for i := range rejections {
+		rejections[i] = dl.buf[(dl.cur-1-i+len(dl.buf))%len(dl.buf)]
+	}
+	return rejections
+}
+
+// deadLetters is a minimal ring buffer of rejections. It can't be a RingBuffer,
+// since RingBuffer[T] can't refer to RingBuffer[Rejection[T]].
+type deadLetters[T any] struct {
+	buf []Rejection[T]
+	cur int
+	len int
+}
+
+// reject records the rejected value, if dead letters are enabled, and returns
+// it as dropped. It assumes the lock is held.
+func (rb *RingBuffer[T]) reject(val T) (dropped T, ok bool) {
+	if dl := rb.deadLetters; dl != nil {
+		dl.buf[dl.cur] = Rejection[T]{Value: val, Time: rb.clock()}
+		dl.cur = (dl.cur + 1) % len(dl.buf)
+		dl.len = min(dl.len+1, len(dl.buf))
+	}
+	return val, true
+}
@@ -0,0 +1,43 @@
+package rb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestRingBufferDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	buf := rb.NewRingBuffer[int](2)
+	buf.EnableTimestamps(clock.Now)
+	buf.SetEvictionPolicy(rb.RejectNew[int]())
+	assertEqual(t, []rb.Rejection[int]{}, buf.DeadLetters())
+
+	for i := range 5 {
+		buf.Add(i) // 2, 3, and 4 are rejected
+		clock.Advance(time.Second)
+	}
+	assertEqual(t, []rb.Rejection[int]{}, buf.DeadLetters()) // not enabled
+
+	buf.SetDeadLetters(2)
+	for i := 5; i < 8; i++ {
+		buf.Add(i)
+		clock.Advance(time.Second)
+	}
+
+	start := clock.Now().Add(-8 * time.Second)
+	assertEqual(t, []rb.Rejection[int]{
+		{Value: 7, Time: start.Add(7 * time.Second)},
+		{Value: 6, Time: start.Add(6 * time.Second)},
+	}, buf.DeadLetters())
+
+	vals, _ := buf.Take(10)
+	assertEqual(t, []int{1, 0}, vals)
+
+	buf.SetDeadLetters(0)
+	assertEqual(t, []rb.Rejection[int]{}, buf.DeadLetters())
+}
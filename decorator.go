@@ -0,0 +1,104 @@
+package rb
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Call describes a single completed call to a method of a Buffer, see
+// InstrumentedBuffer.
+type Call struct {
+	Method   string        // "Add", "Walk", "Take", "Overview", or "Resize"
+	Duration time.Duration // total time spent in the call
+	Count    int           // values added, walked, taken, or counted
+	Dropped  int           // values dropped by Add or Resize
+	Err      error         // error returned by Walk or Take
+}
+
+// InstrumentedBuffer wraps a Buffer, and reports every call made through it to
+// an observer function, which can be used to emit metrics or log lines without
+// changing call sites.
+type InstrumentedBuffer[T any] struct {
+	next    Buffer[T]
+	observe func(Call)
+}
+
+var _ Buffer[int] = (*InstrumentedBuffer[int])(nil)
+
+// NewInstrumentedBuffer returns a Buffer which delegates to next, and calls
+// observe after every call completes. The observe function may be called
+// concurrently.
+func NewInstrumentedBuffer[T any](next Buffer[T], observe func(Call)) *InstrumentedBuffer[T] {
+	return &InstrumentedBuffer[T]{
+		next:    next,
+		observe: observe,
+	}
+}
+
+// Add implements Buffer.
+func (b *InstrumentedBuffer[T]) Add(val T) (dropped T, ok bool) {
+	begin := time.Now()
+	dropped, ok = b.next.Add(val)
+	call := Call{Method: "Add", Duration: time.Since(begin), Count: 1}
+	if ok {
+		call.Dropped = 1
+	}
+	b.observe(call)
+	return dropped, ok
+}
+
+// Walk implements Buffer.
+func (b *InstrumentedBuffer[T]) Walk(fn func(T) error) error {
+	var (
+		begin = time.Now()
+		count int
+	)
+	err := b.next.Walk(func(val T) error {
+		count++
+		return fn(val)
+	})
+	b.observe(Call{Method: "Walk", Duration: time.Since(begin), Count: count, Err: err})
+	return err
+}
+
+// Take implements Buffer.
+func (b *InstrumentedBuffer[T]) Take(n int) ([]T, error) {
+	begin := time.Now()
+	vals, err := b.next.Take(n)
+	b.observe(Call{Method: "Take", Duration: time.Since(begin), Count: len(vals), Err: err})
+	return vals, err
+}
+
+// Overview implements Buffer.
+func (b *InstrumentedBuffer[T]) Overview() (newest, oldest T, count int) {
+	begin := time.Now()
+	newest, oldest, count = b.next.Overview()
+	b.observe(Call{Method: "Overview", Duration: time.Since(begin), Count: count})
+	return newest, oldest, count
+}
+
+// Resize implements Buffer.
+func (b *InstrumentedBuffer[T]) Resize(sz int) (dropped []T) {
+	begin := time.Now()
+	dropped = b.next.Resize(sz)
+	b.observe(Call{Method: "Resize", Duration: time.Since(begin), Dropped: len(dropped)})
+	return dropped
+}
+
+// LogCalls returns an observer function for NewInstrumentedBuffer, which logs
+// every call to the given logger at the given level.
+func LogCalls(logger *slog.Logger, level slog.Level) func(Call) {
+	return func(c Call) {
+		attrs := []slog.Attr{
+			slog.String("method", c.Method),
+			slog.Duration("duration", c.Duration),
+			slog.Int("count", c.Count),
+			slog.Int("dropped", c.Dropped),
+		}
+		if c.Err != nil {
+			attrs = append(attrs, slog.String("err", c.Err.Error()))
+		}
+		logger.LogAttrs(context.Background(), level, "rb call", attrs...)
+	}
+}
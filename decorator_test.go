@@ -0,0 +1,65 @@
+package rb_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestInstrumentedBuffer(t *testing.T) {
+	t.Parallel()
+
+	var calls []rb.Call
+	observe := func(c rb.Call) { calls = append(calls, c) }
+
+	b := rb.NewInstrumentedBuffer[int](rb.NewRingBuffer[int](2), observe)
+	b.Add(1)
+	b.Add(2)
+	b.Add(3)
+	errStop := errors.New("stop")
+	b.Walk(func(int) error { return errStop })
+	b.Take(5)
+	b.Overview()
+	b.Resize(1)
+
+	type summary struct {
+		Method  string
+		Count   int
+		Dropped int
+		Err     bool
+	}
+	var have []summary
+	for _, c := range calls {
+		have = append(have, summary{c.Method, c.Count, c.Dropped, errors.Is(c.Err, errStop)})
+	}
+	assertEqual(t, []summary{
+		{"Add", 1, 0, false},
+		{"Add", 1, 0, false},
+		{"Add", 1, 1, false},
+		{"Walk", 1, 0, true},
+		{"Take", 2, 0, false},
+		{"Overview", 2, 0, false},
+		{"Resize", 0, 1, false},
+	}, have)
+}
+
+func TestLogCalls(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	b := rb.NewInstrumentedBuffer[int](rb.NewRingBuffer[int](1), rb.LogCalls(logger, slog.LevelInfo))
+	b.Add(1)
+	b.Add(2)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assertEqual(t, 2, len(lines))
+	if !strings.Contains(lines[1], "method=Add") || !strings.Contains(lines[1], "dropped=1") {
+		t.Errorf("unexpected log line: %s", lines[1])
+	}
+}
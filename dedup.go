@@ -0,0 +1,60 @@
+package rb
+
+// Run is a value, and the number of consecutive times it was added to a
+// DedupRingBuffer.
+type Run[T any] struct {
+	Value T
+	Count int
+}
+
+// DedupRingBuffer is a ring buffer which collapses consecutive equal values
+// into a single run, with a count, so e.g. a flapping health check that reports
+// the same status repeatedly doesn't fill the buffer with identical values.
+// Only the newest run is extended, so values which are equal but not
+// consecutive are separate runs. The capacity of the buffer is in runs.
+//
+// It's safe for concurrent use by multiple goroutines.
+type DedupRingBuffer[T any] struct {
+	rb *RingBuffer[Run[T]]
+	eq func(a, b T) bool
+}
+
+// NewDedupRingBuffer returns an empty dedup ring buffer of size sz, which uses
+// eq to compare values.
+func NewDedupRingBuffer[T any](sz int, eq func(a, b T) bool) *DedupRingBuffer[T] {
+	return &DedupRingBuffer[T]{
+		rb: NewRingBuffer[Run[T]](sz),
+		eq: eq,
+	}
+}
+
+// Add the value. If it's equal to the value of the newest run, that run's count
+// is incremented. Otherwise, the value starts a new run, and if that evicts the
+// oldest run, the evicted run is returned with true.
+func (drb *DedupRingBuffer[T]) Add(val T) (dropped Run[T], ok bool) {
+	rb := drb.rb
+	rb.lock()
+	defer rb.unlock()
+
+	if rb.len > 0 {
+		if newest := &rb.buf[rb.index(0)]; drb.eq(newest.Value, val) {
+			newest.Count += 1
+			rb.changed()
+			return dropped, false
+		}
+	}
+
+	return rb.add(Run[T]{Value: val, Count: 1})
+}
+
+// Walk calls the given function for each run, starting with the most recent
+// run, and ending with the oldest run. It has the same semantics as
+// RingBuffer.Walk, except that the function must not call Add.
+func (drb *DedupRingBuffer[T]) Walk(fn func(Run[T]) error) error {
+	return drb.rb.Walk(fn)
+}
+
+// Take returns up to the n most recent runs, newest first.
+func (drb *DedupRingBuffer[T]) Take(n int) ([]Run[T], error) {
+	return drb.rb.Take(n)
+}
@@ -0,0 +1,29 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestDedupRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	drb := rb.NewDedupRingBuffer(2, func(a, b string) bool { return a == b })
+	for _, status := range []string{"ok", "ok", "ok", "fail", "fail"} {
+		_, ok := drb.Add(status)
+		assertEqual(t, false, ok)
+	}
+
+	runs, _ := drb.Take(10)
+	assertEqual(t, []rb.Run[string]{{"fail", 2}, {"ok", 3}}, runs)
+
+	// A new run evicts the oldest run.
+	dropped, ok := drb.Add("ok")
+	assertEqual(t, true, ok)
+	assertEqual(t, rb.Run[string]{Value: "ok", Count: 3}, dropped)
+
+	var walked []rb.Run[string]
+	drb.Walk(func(r rb.Run[string]) error { walked = append(walked, r); return nil })
+	assertEqual(t, []rb.Run[string]{{"ok", 1}, {"fail", 2}}, walked)
+}
@@ -0,0 +1,158 @@
+package rb
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// DeltaRingBuffer is a Buffer of integers, which stores each value as the
+// varint-encoded difference from the value before it, and decodes them when
+// they're read. For large monotonic series, like timestamps or counters, where
+// consecutive values are close together, that typically takes 1 or 2 bytes per
+// value, rather than 8. Differences are computed with wraparound, so any
+// series can be stored, but series which jump around don't benefit.
+//
+// Reads decode every value under the lock, so Walk and Take are O(n) in the
+// number of values, regardless of how many are requested. Walk calls fn after
+// the lock is released, so fn may call other methods.
+//
+// It's safe for concurrent use by multiple goroutines.
+type DeltaRingBuffer[T Integer] struct {
+	mtx    sync.Mutex
+	sz     int
+	len    int
+	oldest T
+	newest T
+	data   []byte // encoded differences between consecutive values, oldest first
+	off    int    // offset of the first difference in data
+}
+
+var _ Buffer[int] = (*DeltaRingBuffer[int])(nil)
+
+// NewDeltaRingBuffer returns an empty delta ring buffer of size sz.
+func NewDeltaRingBuffer[T Integer](sz int) *DeltaRingBuffer[T] {
+	return &DeltaRingBuffer[T]{sz: sz}
+}
+
+// Add implements Buffer.
+func (b *DeltaRingBuffer[T]) Add(val T) (dropped T, ok bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.sz <= 0 {
+		return dropped, false
+	}
+
+	if b.len == b.sz {
+		dropped, ok = b.dropOldest(), true
+	}
+
+	if b.len == 0 {
+		b.oldest, b.newest, b.len = val, val, 1
+		return dropped, ok
+	}
+
+	b.data = binary.AppendVarint(b.data, int64(uint64(val)-uint64(b.newest)))
+	b.newest = val
+	b.len++
+	return dropped, ok
+}
+
+// Walk implements Buffer.
+func (b *DeltaRingBuffer[T]) Walk(fn func(T) error) (err error) {
+	defer recoverPanic(&err)
+
+	for _, val := range b.values() {
+		if err := fn(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Take implements Buffer.
+func (b *DeltaRingBuffer[T]) Take(n int) ([]T, error) {
+	vals := b.values()
+	return vals[:min(max(0, n), len(vals))], nil
+}
+
+// Overview implements Buffer. It doesn't decode any values.
+func (b *DeltaRingBuffer[T]) Overview() (newest, oldest T, count int) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.newest, b.oldest, b.len
+}
+
+// Resize implements Buffer.
+func (b *DeltaRingBuffer[T]) Resize(sz int) (dropped []T) {
+	if sz <= 0 {
+		return nil
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for b.len > sz {
+		dropped = append(dropped, b.dropOldest())
+	}
+	for i, j := 0, len(dropped)-1; i < j; i, j = i+1, j-1 {
+		dropped[i], dropped[j] = dropped[j], dropped[i]
+	}
+
+	b.sz = sz
+	return dropped
+}
+
+// SizeBytes returns the size of the encoded differences, in bytes.
+func (b *DeltaRingBuffer[T]) SizeBytes() int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return len(b.data) - b.off
+}
+
+// values decodes and returns all values, newest first.
+func (b *DeltaRingBuffer[T]) values() []T {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	vals := make([]T, b.len)
+	if b.len == 0 {
+		return vals
+	}
+
+	val, data := b.oldest, b.data[b.off:]
+	vals[b.len-1] = val
+	for i := b.len - 2; i >= 0; i-- {
+		d, n := binary.Varint(data)
+		val, data = T(uint64(val)+uint64(d)), data[n:]
+		vals[i] = val
+	}
+	return vals
+}
+
+// dropOldest removes and returns the oldest value. It assumes the lock is held,
+// and the buffer isn't empty.
+func (b *DeltaRingBuffer[T]) dropOldest() T {
+	dropped := b.oldest
+	b.len--
+
+	if b.len == 0 {
+		b.data, b.off = b.data[:0], 0
+		return dropped
+	}
+
+	d, n := binary.Varint(b.data[b.off:])
+	b.oldest = T(uint64(b.oldest) + uint64(d))
+	b.off += n
+
+	// Reclaim the space of dropped differences once it's at least half of the
+	// data, so compaction is amortized over the values added.
+	if b.off >= len(b.data)-b.off {
+		b.data = append(b.data[:0], b.data[b.off:]...)
+		b.off = 0
+	}
+
+	return dropped
+}
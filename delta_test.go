@@ -0,0 +1,79 @@
+package rb_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestDeltaRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	b := rb.NewDeltaRingBuffer[int](3)
+	for _, i := range []int{100, 101, 103} {
+		_, ok := b.Add(i)
+		assertEqual(t, false, ok)
+	}
+
+	dropped, ok := b.Add(99)
+	assertEqual(t, true, ok)
+	assertEqual(t, 100, dropped)
+
+	vals, _ := b.Take(10)
+	assertEqual(t, []int{99, 103, 101}, vals)
+	vals, _ = b.Take(2)
+	assertEqual(t, []int{99, 103}, vals)
+
+	newest, oldest, count := b.Overview()
+	assertEqual(t, []int{99, 101, 3}, []int{newest, oldest, count})
+
+	assertEqual(t, []int{103, 101}, b.Resize(1))
+	vals, _ = b.Take(10)
+	assertEqual(t, []int{99}, vals)
+	assertEqual(t, 0, b.SizeBytes())
+}
+
+func TestDeltaRingBufferWraparound(t *testing.T) {
+	t.Parallel()
+
+	u := rb.NewDeltaRingBuffer[uint8](10)
+	for _, i := range []uint8{250, 255, 3, 0, 255} {
+		u.Add(i)
+	}
+	uvals, _ := u.Take(10)
+	assertEqual(t, []uint8{255, 0, 3, 255, 250}, uvals)
+
+	s := rb.NewDeltaRingBuffer[int64](10)
+	for _, i := range []int64{math.MinInt64, math.MaxInt64, 0, -1, math.MinInt64} {
+		s.Add(i)
+	}
+	svals, _ := s.Take(10)
+	assertEqual(t, []int64{math.MinInt64, -1, 0, math.MaxInt64, math.MinInt64}, svals)
+}
+
+func TestDeltaRingBufferMatchesRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		b   = rb.NewDeltaRingBuffer[int64](100)
+		ref = rb.NewRingBuffer[int64](100)
+		ts  = time.Now().UnixNano()
+	)
+	for range 1000 {
+		ts += rand.Int64N(int64(time.Millisecond))
+		b.Add(ts)
+		ref.Add(ts)
+	}
+
+	want, _ := ref.Take(100)
+	have, _ := b.Take(100)
+	assertEqual(t, want, have)
+
+	// Nanosecond timestamps a millisecond apart take at most 3 bytes each.
+	if n := b.SizeBytes(); n > 3*99 {
+		t.Errorf("SizeBytes: want <= %d, have %d", 3*99, n)
+	}
+}
@@ -0,0 +1,41 @@
+package rb
+
+// Difference is the result of Diff. Each slice is ordered newest first.
+type Difference[T any] struct {
+	OnlyA  []T // values in a without an equal value in b
+	OnlyB  []T // values in b without an equal value in a
+	Common []T // values in a with an equal value in b
+}
+
+// Diff compares the values in two ring buffers, e.g. a snapshot from Label and
+// the current ring buffer, and reports the values only in a, only in b, and in
+// both. Each ring buffer is read from a consistent snapshot, taken under its
+// lock. Values are compared as multisets, so each value in a is matched with
+// at most one equal value in b. The comparison is O(len(a) * len(b)) calls to
+// eq. This is a function rather than a method, for symmetry.
+func Diff[T any](a, b *RingBuffer[T], eq func(T, T) bool) Difference[T] {
+	as, bs := a.values(), b.values()
+
+	d := Difference[T]{OnlyA: []T{}, OnlyB: []T{}, Common: []T{}}
+	matched := make([]bool, len(bs))
+	for _, av := range as {
+		found := false
+		for j, bv := range bs {
+			if !matched[j] && eq(av, bv) {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if found {
+			d.Common = append(d.Common, av)
+		} else {
+			d.OnlyA = append(d.OnlyA, av)
+		}
+	}
+	for j, bv := range bs {
+		if !matched[j] {
+			d.OnlyB = append(d.OnlyB, bv)
+		}
+	}
+	return d
+}
@@ -0,0 +1,31 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](4)
+	for _, v := range []int{1, 2, 2, 3} {
+		buf.Add(v)
+	}
+	buf.Label("before")
+
+	buf.Add(4)
+	buf.Add(2)
+
+	before, _ := buf.Snapshot("before")
+	d := rb.Diff(before, buf, func(a, b int) bool { return a == b })
+	assertEqual(t, rb.Difference[int]{
+		OnlyA:  []int{1},
+		OnlyB:  []int{4},
+		Common: []int{3, 2, 2},
+	}, d)
+
+	d = rb.Diff(buf, buf, func(a, b int) bool { return a == b })
+	assertEqual(t, rb.Difference[int]{OnlyA: []int{}, OnlyB: []int{}, Common: []int{2, 4, 3, 2}}, d)
+}
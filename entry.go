@@ -0,0 +1,59 @@
+package rb
+
+import "time"
+
+// Entry is a value in a ring buffer, along with its sequence number. Every
+// value added to a ring buffer is assigned a sequence number, starting at 0,
+// and increasing by 1 with each add, so they identify values uniquely, and
+// can be used to resume reading from a particular point.
+//
+// If timestamps are enabled, see EnableTimestamps, an entry also includes the
+// time its value was added. Otherwise, the time is zero.
+type Entry[T any] struct {
+	Seq   uint64    `json:"seq"`
+	Value T         `json:"value"`
+	Time  time.Time `json:"time,omitzero"`
+}
+
+// WalkEntries is like Walk, but calls the function with entries, which include
+// the sequence number and timestamp of each value.
+func (rb *RingBuffer[T]) WalkEntries(fn func(Entry[T]) error) (err error) {
+	defer recoverPanic(&err)
+
+	op := rb.startOp("Walk")
+	defer op.done()
+
+	rb.walk(func() {
+		op.acquired()
+		for i := range rb.len {
+			if err = fn(rb.entry(i)); err != nil {
+				return
+			}
+		}
+	})
+	return err
+}
+
+// TakeEntries is like Take, but returns entries, which include the sequence
+// number and timestamp of each value.
+func (rb *RingBuffer[T]) TakeEntries(n int) ([]Entry[T], error) {
+	rb.lock()
+	defer rb.unlock()
+
+	entries := make([]Entry[T], min(max(0, n), rb.len))
+	for i := range entries {
+		entries[i] = rb.entry(i)
+	}
+	return entries, nil
+}
+
+// entry returns the i'th newest value as an entry. It assumes the lock is held.
+func (rb *RingBuffer[T]) entry(i int) Entry[T] {
+	e := Entry[T]{Seq: rb.seqAt(i), Value: rb.buf[rb.index(i)]}
+	if rb.now != nil {
+		if t := rb.meta[rb.index(i)].time; t != 0 {
+			e.Time = time.Unix(0, t)
+		}
+	}
+	return e
+}
@@ -0,0 +1,41 @@
+package rb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestEntries(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	start := clock.Now()
+
+	buf := rb.NewRingBuffer[string](3)
+	buf.Add("a") // before timestamps are enabled
+	buf.EnableTimestamps(clock.Now)
+	buf.Add("b")
+	clock.Advance(time.Second)
+	buf.Add("c")
+
+	entries, err := buf.TakeEntries(10)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []rb.Entry[string]{
+		{Seq: 2, Value: "c", Time: start.Add(time.Second)},
+		{Seq: 1, Value: "b", Time: start},
+		{Seq: 0, Value: "a"},
+	}, entries)
+
+	var walked []rb.Entry[string]
+	assertEqual(t, error(nil), buf.WalkEntries(func(e rb.Entry[string]) error {
+		walked = append(walked, e)
+		return nil
+	}))
+	assertEqual(t, entries, walked)
+
+	entries, _ = buf.TakeEntries(1)
+	assertEqual(t, []rb.Entry[string]{{Seq: 2, Value: "c", Time: start.Add(time.Second)}}, entries)
+}
@@ -0,0 +1,23 @@
+package rb
+
+import (
+	"fmt"
+)
+
+// PanicError is returned by methods like Walk, when the caller-provided function
+// panics. The panic is recovered, and the ring buffer remains usable.
+type PanicError struct {
+	Value any    // value passed to panic
+	Stack []byte // stack trace of the panicking goroutine
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("rb: recovered panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value, if it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
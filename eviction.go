@@ -0,0 +1,67 @@
+package rb
+
+// EvictionPolicy decides which value is evicted when a value is added to a
+// full ring buffer. By default, the oldest value is evicted.
+type EvictionPolicy[T any] interface {
+	// Evict is called with the incoming value, and a view of the values in the
+	// ring buffer, which is full. It returns the index in that view of the
+	// value to evict, where 0 is the newest value, and values.Len()-1 is the
+	// oldest value. Any other index, e.g. -1, rejects the incoming value
+	// instead. Evict is called with the lock held, so it must not call any
+	// methods on the ring buffer, and the view is only valid for the duration
+	// of the call.
+	Evict(incoming T, values View[T]) int
+}
+
+// EvictionFunc adapts a function to an EvictionPolicy.
+type EvictionFunc[T any] func(incoming T, values View[T]) int
+
+// Evict implements EvictionPolicy.
+func (f EvictionFunc[T]) Evict(incoming T, values View[T]) int {
+	return f(incoming, values)
+}
+
+// EvictOldest is the default eviction policy, which evicts the oldest value.
+func EvictOldest[T any]() EvictionPolicy[T] {
+	return EvictionFunc[T](func(_ T, values View[T]) int { return values.Len() - 1 })
+}
+
+// RejectNew is an eviction policy which never evicts a value, and rejects the
+// incoming value instead. Add returns a rejected value as dropped.
+func RejectNew[T any]() EvictionPolicy[T] {
+	return EvictionFunc[T](func(T, View[T]) int { return -1 })
+}
+
+// View provides read-only access to the values in a ring buffer, while the lock
+// is held by the caller.
+type View[T any] struct {
+	rb *RingBuffer[T]
+}
+
+// Len returns the number of values in the view.
+func (v View[T]) Len() int {
+	return v.rb.len
+}
+
+// At returns the i'th newest value in the view, where i=0 is the newest value,
+// and i=Len()-1 is the oldest value.
+func (v View[T]) At(i int) T {
+	return v.rb.buf[v.rb.index(i)]
+}
+
+// SetEvictionPolicy sets the eviction policy of the ring buffer. If policy is
+// nil, the default policy, EvictOldest, is used.
+//
+// Policies which evict values other than the oldest value are O(n) in the size
+// of the ring buffer, as values are shifted to fill the gap.
+func (rb *RingBuffer[T]) SetEvictionPolicy(policy EvictionPolicy[T]) {
+	rb.lock()
+	defer rb.unlock()
+
+	if policy != nil {
+		rb.trackMeta()
+	}
+
+	rb.policy = policy
+	rb.setHooks()
+}
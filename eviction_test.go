@@ -0,0 +1,80 @@
+package rb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestEvictionPolicy(t *testing.T) {
+	t.Parallel()
+
+	// Evict the largest value.
+	largest := rb.EvictionFunc[int](func(incoming int, values rb.View[int]) int {
+		index := 0
+		for i := range values.Len() {
+			if values.At(i) > values.At(index) {
+				index = i
+			}
+		}
+		return index
+	})
+
+	rb := rb.NewRingBuffer[int](4)
+	cp := rb.Mark()
+	rb.SetEvictionPolicy(largest)
+
+	for _, i := range []int{5, 1, 9, 3} {
+		rb.Add(i)
+	}
+
+	dropped, ok := rb.Add(2)
+	assertEqual(t, 9, dropped)
+	assertEqual(t, true, ok)
+
+	vals, _ := rb.Take(10)
+	assertEqual(t, []int{2, 3, 1, 5}, vals)
+
+	// Sequence numbers account for the gap.
+	vals, ok = rb.SinceCheckpoint(cp)
+	assertEqual(t, []int{2, 3, 1, 5}, vals)
+	assertEqual(t, false, ok)
+
+	// Resize preserves sequence numbers, too.
+	rb.Resize(8)
+	rb.Add(4)
+	cp = rb.Mark()
+	rb.Add(6)
+	vals, ok = rb.SinceCheckpoint(cp)
+	assertEqual(t, []int{6}, vals)
+	assertEqual(t, true, ok)
+
+	// Subscribers see every remaining value, in order.
+	ctx, cancel := context.WithCancel(context.Background())
+	var seen []int
+	rb.Subscribe(ctx, func(i int) error {
+		if seen = append(seen, i); len(seen) == 6 {
+			cancel()
+		}
+		return nil
+	})
+	assertEqual(t, []int{5, 1, 3, 2, 4, 6}, seen)
+}
+
+func TestRejectNew(t *testing.T) {
+	t.Parallel()
+
+	policy := rb.RejectNew[int]()
+	rb := rb.NewRingBuffer[int](2)
+	rb.SetEvictionPolicy(policy)
+	rb.Add(1)
+	rb.Add(2)
+
+	dropped, ok := rb.Add(3)
+	assertEqual(t, 3, dropped)
+	assertEqual(t, true, ok)
+
+	vals, _ := rb.Take(10)
+	assertEqual(t, []int{2, 1}, vals)
+}
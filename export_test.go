@@ -0,0 +1,42 @@
+package rb
+
+import "io"
+
+// ShortWriteWAL makes the next write to the current segment of w write at
+// most n bytes, and then fail.
+func ShortWriteWAL[T any](w *WAL[T], n int) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.f = &shortWriteFile{walFile: w.f, n: n}
+}
+
+type shortWriteFile struct {
+	walFile
+	n int // negative once the short write has happened
+}
+
+func (f *shortWriteFile) Write(p []byte) (int, error) {
+	if f.n < 0 {
+		return f.walFile.Write(p)
+	}
+	n, _ := f.walFile.Write(p[:min(len(p), f.n)])
+	f.n = -1
+	return n, io.ErrShortWrite
+}
+
+// SetResizeRoundHook sets a function to call between the rounds of every
+// ResizeIncremental, and returns a function which removes it. Tests which use
+// it mustn't be parallel.
+func SetResizeRoundHook(fn func()) (remove func()) {
+	testHookResizeRound = fn
+	return func() { testHookResizeRound = nil }
+}
+
+// BackingArray returns the backing array of rb, including unused slots.
+func BackingArray[T any](rb *RingBuffer[T]) []T {
+	rb.lock()
+	defer rb.unlock()
+
+	return append([]T{}, rb.buf...)
+}
@@ -0,0 +1,88 @@
+package rb
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Follower maintains a local replica of a remote ring buffer, served by a
+// TailHandler. Downstream code reads the replica via RingBuffer, with the
+// normal API. If the connection fails, the follower reconnects, and resumes
+// from the sequence number after the last value it received, so values aren't
+// duplicated, and are only lost if the remote ring buffer drops them while the
+// follower is disconnected.
+//
+// If the remote ring buffer is replaced, e.g. because its process restarts, its
+// sequence numbers start over, and the follower resumes from its oldest value,
+// and reports that via OnReset.
+type Follower[T any] struct {
+	// Client is used to connect to the remote ring buffer. If it's nil, the
+	// default client is used.
+	Client *http.Client
+
+	// Backoff is the delay before each reconnect. If it's zero, 1s is used.
+	Backoff time.Duration
+
+	// OnError, if non-nil, is called with every error which causes a reconnect.
+	OnError func(error)
+
+	// OnReset, if non-nil, is called when the remote ring buffer is behind the
+	// follower, e.g. because it restarted, with the sequence number the
+	// follower expected, and the one it resumed from.
+	OnReset func(from, to uint64)
+
+	url string
+	rb  *RingBuffer[T]
+}
+
+// NewFollower returns a follower of the TailHandler at the given URL, with a
+// local replica of size sz. Exported fields should be set before calling Run.
+func NewFollower[T any](url string, sz int) *Follower[T] {
+	return &Follower[T]{
+		url: url,
+		rb:  NewRingBuffer[T](sz),
+	}
+}
+
+// RingBuffer returns the local replica.
+func (f *Follower[T]) RingBuffer() *RingBuffer[T] {
+	return f.rb
+}
+
+// Run connects to the remote ring buffer, and adds every value it receives to
+// the local replica, reconnecting as necessary, until the context is done.
+func (f *Follower[T]) Run(ctx context.Context) error {
+	backoff := f.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	var next uint64
+	for {
+		var err error
+		expect := next
+		next, err = Tail(ctx, f.Client, f.url, next, func(e Entry[T]) error {
+			if e.Seq < expect && f.OnReset != nil {
+				f.OnReset(expect, e.Seq)
+			}
+			expect = e.Seq + 1
+			f.rb.Add(e.Value)
+			return nil
+		})
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil && f.OnError != nil {
+			f.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
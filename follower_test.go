@@ -0,0 +1,118 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestFollower(t *testing.T) {
+	t.Parallel()
+
+	src := rb.NewRingBuffer[int](5)
+	src.Add(1)
+	src.Add(2)
+
+	server := httptest.NewServer(src.TailHandler())
+	defer server.Close()
+
+	f := rb.NewFollower[int](server.URL, 3)
+	f.Backoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- f.Run(ctx) }()
+
+	waitFor := func(want []int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			have, _ := f.RingBuffer().Take(10)
+			if len(have) > 0 && have[0] == want[0] {
+				assertEqual(t, want, have)
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout: want %v, have %v", want, have)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitFor([]int{2, 1})
+	src.Add(3)
+	waitFor([]int{3, 2, 1})
+
+	// After a disconnect, the follower resumes without duplicates.
+	server.CloseClientConnections()
+	src.Add(4)
+	waitFor([]int{4, 3, 2})
+	server.CloseClientConnections()
+	src.Add(5)
+	src.Add(6)
+	waitFor([]int{6, 5, 4})
+
+	cancel()
+	assertEqual(t, true, errors.Is(<-done, context.Canceled))
+}
+
+func TestFollowerRestart(t *testing.T) {
+	t.Parallel()
+
+	var src atomic.Pointer[rb.RingBuffer[int]]
+	src.Store(rb.NewRingBuffer[int](5))
+	src.Load().Add(1)
+	src.Load().Add(2)
+	src.Load().Add(3)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		src.Load().TailHandler().ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	resets := make(chan [2]uint64, 1)
+	f := rb.NewFollower[int](server.URL, 5)
+	f.Backoff = time.Millisecond
+	f.OnReset = func(from, to uint64) { resets <- [2]uint64{from, to} }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- f.Run(ctx) }()
+
+	waitFor := func(want []int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			have, _ := f.RingBuffer().Take(10)
+			if len(have) > 0 && have[0] == want[0] {
+				assertEqual(t, want, have)
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout: want %v, have %v", want, have)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitFor([]int{3, 2, 1})
+
+	// The remote restarts with a new ring buffer, whose sequence numbers start
+	// over, so the follower resumes from its oldest value.
+	restarted := rb.NewRingBuffer[int](5)
+	restarted.Add(10)
+	restarted.Add(20)
+	src.Store(restarted)
+	server.CloseClientConnections()
+	waitFor([]int{20, 10, 3, 2, 1})
+	assertEqual(t, [2]uint64{3, 0}, <-resets)
+
+	cancel()
+	assertEqual(t, true, errors.Is(<-done, context.Canceled))
+}
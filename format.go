@@ -0,0 +1,129 @@
+package rb
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// formatMaxValues is the maximum number of values printed by %+v.
+const formatMaxValues = 10
+
+var (
+	_ fmt.Formatter = (*RingBuffer[int])(nil)
+	_ fmt.Formatter = (*RingBuffers[int])(nil)
+)
+
+// String returns a concise summary of the ring buffer: its name, if it has one,
+// its length and capacity, the newest and oldest values, and, if timestamps are
+// enabled, the age of the oldest value.
+func (rb *RingBuffer[T]) String() string {
+	return rb.snapshot(false).summary()
+}
+
+// Format implements fmt.Formatter. The %v and %s verbs print the summary
+// returned by String, and %+v also prints the newest values, up to 10.
+func (rb *RingBuffer[T]) Format(f fmt.State, verb rune) {
+	contents := verb == 'v' && f.Flag('+')
+	s := rb.snapshot(contents)
+
+	io.WriteString(f, s.summary())
+	if contents {
+		io.WriteString(f, " ")
+		io.WriteString(f, s.contents())
+	}
+}
+
+// formatSnapshot is what String and Format print, copied under the lock, so
+// values are formatted without holding it.
+type formatSnapshot[T any] struct {
+	name           string
+	len, cap       int
+	newest, oldest T
+	oldestTime     int64 // or 0
+	now            func() time.Time
+	values         []T // newest first, up to formatMaxValues
+}
+
+// snapshot returns what String and Format print, including the newest values
+// if contents is true.
+func (rb *RingBuffer[T]) snapshot(contents bool) formatSnapshot[T] {
+	rb.lock()
+	defer rb.unlock()
+
+	s := formatSnapshot[T]{
+		name: rb.info.Name,
+		len:  rb.len,
+		cap:  len(rb.buf),
+		now:  rb.now,
+	}
+	if rb.len > 0 {
+		s.newest, s.oldest = rb.buf[rb.index(0)], rb.buf[rb.oldest()]
+		if rb.now != nil {
+			s.oldestTime = rb.meta[rb.oldest()].time
+		}
+	}
+	if contents {
+		s.values = make([]T, min(rb.len, formatMaxValues))
+		for i := range s.values {
+			s.values[i] = rb.buf[rb.index(i)]
+		}
+	}
+	return s
+}
+
+func (s formatSnapshot[T]) summary() string {
+	var sb strings.Builder
+	sb.WriteString("RingBuffer{")
+	if s.name != "" {
+		fmt.Fprintf(&sb, "name=%s ", s.name)
+	}
+	fmt.Fprintf(&sb, "len=%d cap=%d", s.len, s.cap)
+	if s.len > 0 {
+		fmt.Fprintf(&sb, " newest=%v oldest=%v", s.newest, s.oldest)
+	}
+	if s.now != nil && s.oldestTime != 0 {
+		fmt.Fprintf(&sb, " age=%s", s.now().Sub(time.Unix(0, s.oldestTime)).Round(time.Millisecond))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func (s formatSnapshot[T]) contents() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i, v := range s.values {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		fmt.Fprintf(&sb, "%v", v)
+	}
+	if n := s.len - formatMaxValues; n > 0 {
+		fmt.Fprintf(&sb, " …%d more", n)
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// String returns a concise summary of the set: the number of categories, and
+// the total length and capacity of their ring buffers.
+func (rbs *RingBuffers[T]) String() string {
+	total, categories := rbs.Stats()
+	return fmt.Sprintf("RingBuffers{categories=%d len=%d cap=%d}", len(categories), total.Count, total.Capacity)
+}
+
+// Format implements fmt.Formatter. The %v and %s verbs print the summary
+// returned by String, and %+v also prints each category, sorted by name, with
+// the %+v format of its ring buffer.
+func (rbs *RingBuffers[T]) Format(f fmt.State, verb rune) {
+	io.WriteString(f, rbs.String())
+	if verb == 'v' && f.Flag('+') {
+		all := rbs.GetAll()
+		for _, category := range slices.Sorted(maps.Keys(all)) {
+			fmt.Fprintf(f, "\n\t%s: %+v", category, all[category])
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package rb_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](20)
+	assertEqual(t, "RingBuffer{len=0 cap=20}", buf.String())
+	assertEqual(t, "RingBuffer{len=0 cap=20} []", fmt.Sprintf("%+v", buf))
+
+	for i := range 12 {
+		buf.Add(i)
+	}
+	assertEqual(t, "RingBuffer{len=12 cap=20 newest=11 oldest=0}", fmt.Sprint(buf))
+	assertEqual(t, "RingBuffer{len=12 cap=20 newest=11 oldest=0} [11 10 9 8 7 6 5 4 3 2 …2 more]", fmt.Sprintf("%+v", buf))
+
+	clock := rbtest.NewClock(epoch)
+	buf = rb.NewRingBuffer[int](3)
+	buf.EnableTimestamps(clock.Now)
+	buf.Add(1)
+	clock.Advance(1500 * time.Millisecond)
+	buf.Add(2)
+	assertEqual(t, "RingBuffer{len=2 cap=3 newest=2 oldest=1 age=1.5s}", fmt.Sprintf("%s", buf))
+
+	rbs := rb.NewRingBuffers[string](2)
+	rbs.GetOrCreate("b").Add("x")
+	rbs.GetOrCreate("a")
+	assertEqual(t, "RingBuffers{categories=2 len=1 cap=4}", rbs.String())
+	assertEqual(t, "RingBuffers{categories=2 len=1 cap=4}\n\ta: RingBuffer{len=0 cap=2} []\n\tb: RingBuffer{len=1 cap=2 newest=x oldest=x} [x]", fmt.Sprintf("%+v", rbs))
+}
+
+type stringerFunc func() string
+
+func (f stringerFunc) String() string { return f() }
+
+func TestFormatReentrant(t *testing.T) {
+	t.Parallel()
+
+	// Values are formatted without holding the lock, so they can read the
+	// ring buffer that holds them.
+	buf := rb.NewRingBuffer[fmt.Stringer](2)
+	buf.Add(stringerFunc(func() string { return fmt.Sprint(buf.Stats().Count) }))
+	assertEqual(t, "RingBuffer{len=1 cap=2 newest=1 oldest=1}", buf.String())
+	assertEqual(t, "RingBuffer{len=1 cap=2 newest=1 oldest=1} [1]", fmt.Sprintf("%+v", buf))
+}
@@ -0,0 +1,77 @@
+package rb
+
+import "time"
+
+// RemoveEmptyAfter enables garbage collection of empty categories. Ring buffers
+// which have been empty for at least the given period, because they were never
+// added to, or were drained or cleared, are removed from the set, along with
+// their backing arrays. This bounds the memory held by one-shot categories.
+//
+// Collection is lazy: categories are checked at most once per period, by
+// GetOrCreate and GetAll, so an empty category is removed between one and two
+// periods after it becomes empty. Values added to a removed ring buffer, via a
+// reference obtained before it was removed, are no longer part of the set, so
+// callers should call GetOrCreate for each use. If d <= 0, collection is
+// disabled.
+func (rbs *RingBuffers[T]) RemoveEmptyAfter(d time.Duration) {
+	rbs.mtx.Lock()
+	defer rbs.mtx.Unlock()
+
+	rbs.emptyTTL = max(0, d)
+	rbs.emptySince = nil
+	rbs.lastSweep = rbs.clock()
+}
+
+// SetClock sets the clock used to collect empty categories, e.g. to a fake
+// clock in tests. If now is nil, time.Now is used.
+func (rbs *RingBuffers[T]) SetClock(now func() time.Time) {
+	rbs.mtx.Lock()
+	defer rbs.mtx.Unlock()
+
+	rbs.now = now
+}
+
+// clock returns the current time. It assumes the lock is held.
+func (rbs *RingBuffers[T]) clock() time.Time {
+	if rbs.now != nil {
+		return rbs.now()
+	}
+	return time.Now()
+}
+
+// collectEmpty removes categories which have been empty for longer than the
+// empty TTL, if it's time for a sweep. Ring buffers which are locked, e.g. by a
+// walk, are skipped until the next sweep. It assumes the lock is held.
+func (rbs *RingBuffers[T]) collectEmpty() {
+	if rbs.emptyTTL <= 0 {
+		return
+	}
+
+	now := rbs.clock()
+	if now.Sub(rbs.lastSweep) < rbs.emptyTTL {
+		return
+	}
+	rbs.lastSweep = now
+
+	if rbs.emptySince == nil {
+		rbs.emptySince = map[string]time.Time{}
+	}
+	for name, rb := range rbs.bufs {
+		if !rb.tryLock() {
+			continue
+		}
+		empty := rb.len == 0
+		rb.unlock()
+
+		since, seen := rbs.emptySince[name]
+		switch {
+		case !empty:
+			delete(rbs.emptySince, name)
+		case !seen:
+			rbs.emptySince[name] = now
+		case now.Sub(since) >= rbs.emptyTTL:
+			delete(rbs.bufs, name)
+			delete(rbs.emptySince, name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package rb_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestRemoveEmptyAfter(t *testing.T) {
+	t.Parallel()
+
+	const period = time.Minute
+
+	clock := rbtest.NewClock(epoch)
+	rbs := rb.NewRingBuffers[int](3)
+	rbs.SetClock(clock.Now)
+	rbs.RemoveEmptyAfter(period)
+	rbs.GetOrCreate("busy").Add(1)
+	rbs.GetOrCreate("oneshot")
+	rbs.GetOrCreate("drained").Add(1)
+
+	categories := func() []string {
+		return slices.Sorted(maps.Keys(rbs.GetAll()))
+	}
+
+	// Nothing is checked until a period has passed.
+	clock.Advance(period / 2)
+	assertEqual(t, []string{"busy", "drained", "oneshot"}, categories())
+
+	// The first sweep notices the empty category.
+	clock.Advance(period / 2)
+	assertEqual(t, []string{"busy", "drained", "oneshot"}, categories())
+	rbs.GetOrCreate("drained").Clear()
+
+	// The next sweep removes it, and notices the newly empty category.
+	clock.Advance(period)
+	assertEqual(t, []string{"busy", "drained"}, categories())
+
+	clock.Advance(period)
+	assertEqual(t, []string{"busy"}, categories())
+
+	// Removed categories are recreated on demand.
+	rbs.GetOrCreate("oneshot").Add(2)
+	assertEqual(t, []string{"busy", "oneshot"}, categories())
+}
+
+func TestRemoveEmptyAfterDuringWalk(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	rbs := rb.NewRingBuffers[int](3)
+	rbs.SetClock(clock.Now)
+	rbs.RemoveEmptyAfter(time.Minute)
+	rbs.GetOrCreate("empty")
+	rbs.GetOrCreate("walked").Add(1)
+
+	// A sweep during a walk skips the walked ring buffer, rather than waiting
+	// for the walk to complete.
+	rbs.GetOrCreate("walked").Walk(func(int) error {
+		clock.Advance(time.Minute)
+		rbs.GetAll()
+		clock.Advance(time.Minute)
+		rbs.GetOrCreate("other")
+		return nil
+	})
+	assertEqual(t, []string{"other", "walked"}, slices.Sorted(maps.Keys(rbs.GetAll())))
+}
@@ -0,0 +1,167 @@
+package rb
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// HTMLOptions control RenderHTML.
+type HTMLOptions struct {
+	// Title of the page. If it's empty, a default is used.
+	Title string
+
+	// Limit is the maximum number of values rendered, newest first. If it's
+	// zero, all values are rendered.
+	Limit int
+
+	// Category, for RingBuffers, renders the contents of that category, rather
+	// than the index of categories.
+	Category string
+
+	// Link, for RingBuffers, returns the URL of the page for each category in
+	// the index. If it's nil, the URL is the query parameter category, which
+	// works with a handler that passes it through to Category.
+	Link func(category string) string
+}
+
+// RenderHTML writes a simple HTML page with a table of the values in the ring
+// buffer, newest first, with their sequence numbers, and timestamps if they're
+// enabled. The page includes the ring buffer's info, if any. Columns can be
+// sorted by clicking their headers. Values are formatted with fmt, and
+// escaped.
+func (rb *RingBuffer[T]) RenderHTML(w io.Writer, opts HTMLOptions) error {
+	entries, err := rb.TakeEntries(rb.Stats().Capacity)
+	if err != nil {
+		return err
+	}
+	if opts.Limit > 0 && len(entries) > opts.Limit {
+		entries = entries[:opts.Limit]
+	}
+
+	info := rb.Info()
+	page := htmlPage{Title: opts.Title, Description: info.Description, Header: []string{"seq", "time", "value"}}
+	if page.Title == "" {
+		page.Title = cmp.Or(info.Name, "Ring buffer")
+	}
+	if info.Unit != "" {
+		page.Header[2] = fmt.Sprintf("value (%s)", info.Unit)
+	}
+	for _, k := range slices.Sorted(maps.Keys(info.Labels)) {
+		page.Labels = append(page.Labels, fmt.Sprintf("%s=%s", k, info.Labels[k]))
+	}
+	for _, e := range entries {
+		var ts string
+		if !e.Time.IsZero() {
+			ts = e.Time.Format(time.RFC3339Nano)
+		}
+		page.Rows = append(page.Rows, htmlRow{Cells: []string{fmt.Sprint(e.Seq), ts, fmt.Sprintf("%v", e.Value)}})
+	}
+
+	return htmlTemplate.Execute(w, page)
+}
+
+// RenderHTML writes a simple HTML page with an index of the categories in the
+// set, with links to each category, or the contents of a single category, if
+// it's set in the options. See RingBuffer.RenderHTML.
+func (rbs *RingBuffers[T]) RenderHTML(w io.Writer, opts HTMLOptions) error {
+	all := rbs.GetAll()
+
+	if opts.Category != "" {
+		rb, ok := all[opts.Category]
+		if !ok {
+			return fmt.Errorf("category %q not found", opts.Category)
+		}
+		if opts.Title == "" {
+			opts.Title = opts.Category
+		}
+		return rb.RenderHTML(w, opts)
+	}
+
+	link := opts.Link
+	if link == nil {
+		link = func(category string) string { return "?" + url.Values{"category": {category}}.Encode() }
+	}
+
+	page := htmlPage{Title: opts.Title, Header: []string{"category", "len", "cap", "added"}}
+	if page.Title == "" {
+		page.Title = "Ring buffers"
+	}
+	for _, category := range slices.Sorted(maps.Keys(all)) {
+		stats := all[category].Stats()
+		page.Rows = append(page.Rows, htmlRow{
+			Link:  link(category),
+			Cells: []string{category, fmt.Sprint(stats.Count), fmt.Sprint(stats.Capacity), fmt.Sprint(stats.Added)},
+		})
+	}
+
+	return htmlTemplate.Execute(w, page)
+}
+
+type htmlPage struct {
+	Title       string
+	Description string
+	Labels      []string
+	Header      []string
+	Rows        []htmlRow
+}
+
+// htmlRow is a row of cells. If Link is set, the first cell links to it.
+type htmlRow struct {
+	Link  string
+	Cells []string
+}
+
+var htmlTemplate = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.5em; text-align: left; vertical-align: top; }
+th { cursor: pointer; background: #eee; }
+td { font-family: monospace; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+{{- with .Labels}}
+<p>{{range .}}<code>{{.}}</code> {{end}}</p>
+{{- end}}
+<table>
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{$link := .Link}}{{range $i, $cell := .Cells}}<td>{{if and $link (eq $i 0)}}<a href="{{$link}}">{{$cell}}</a>{{else}}{{$cell}}{{end}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+document.querySelectorAll("th").forEach(function (th, col) {
+	th.addEventListener("click", function () {
+		var tbody = th.closest("table").tBodies[0];
+		var asc = th.dataset.order !== "asc";
+		th.dataset.order = asc ? "asc" : "desc";
+		var rows = Array.from(tbody.rows);
+		rows.sort(function (a, b) {
+			var x = a.cells[col].textContent, y = b.cells[col].textContent;
+			var c = (x !== "" && y !== "" && !isNaN(x) && !isNaN(y)) ? x - y : x.localeCompare(y);
+			return asc ? c : -c;
+		});
+		rows.forEach(function (row) { tbody.appendChild(row); });
+	});
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,32 @@
+package rb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRenderHTML(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[string](5)
+	rbs.GetOrCreate("http").Add("GET /")
+	rbs.GetOrCreate("http").Add("<script>")
+	rbs.GetOrCreate("a&b")
+
+	var sb strings.Builder
+	assertEqual(t, error(nil), rbs.RenderHTML(&sb, rb.HTMLOptions{}))
+	page := sb.String()
+	assertEqual(t, true, strings.Contains(page, `<a href="?category=http">http</a>`))
+	assertEqual(t, true, strings.Contains(page, `<a href="?category=a%26b">a&amp;b</a>`))
+
+	sb.Reset()
+	assertEqual(t, error(nil), rbs.RenderHTML(&sb, rb.HTMLOptions{Category: "http", Limit: 1}))
+	page = sb.String()
+	assertEqual(t, true, strings.Contains(page, "<title>http</title>"))
+	assertEqual(t, true, strings.Contains(page, "<td>1</td><td></td><td>&lt;script&gt;</td>"))
+	assertEqual(t, false, strings.Contains(page, "GET /"))
+
+	assertEqual(t, true, rbs.RenderHTML(&sb, rb.HTMLOptions{Category: "missing"}) != nil)
+}
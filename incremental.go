@@ -0,0 +1,103 @@
+package rb
+
+// ResizeIncremental is like Resize, but copies values into the new backing
+// array in chunks of at most chunk values, releasing the lock between chunks,
+// so that the lock is never held for long, even for very large ring buffers.
+// Adds made while the resize is in progress are picked up by later chunks, and
+// the new backing array replaces the old one in a final, brief step. Values
+// dropped by the resize are returned as with Resize, and are collected in that
+// final step. So that concurrent adds can't keep the resize going forever, the
+// number of chunks is limited to what a full ring buffer of the new size needs,
+// and whatever is left after that is copied in the final step.
+//
+// If per-value metadata is tracked, e.g. because an eviction policy, TTLs, or
+// timestamps are in use, then values can't be tracked between chunks, and the
+// method falls back to Resize. If sz <= 0 it's ignored and the method is a
+// no-op, and if chunk <= 0 it's treated as 1.
+func (rb *RingBuffer[T]) ResizeIncremental(sz, chunk int) (dropped []T) {
+	// Safety first.
+	if sz <= 0 {
+		return nil
+	}
+	chunk = max(1, chunk)
+
+	// Without metadata, sequence numbers are contiguous, and each value can be
+	// located by its sequence number, across chunks. Values are written to the
+	// new backing array at the index of their sequence number modulo sz.
+	rb.lock()
+	if rb.meta != nil {
+		rb.unlock()
+		return rb.Resize(sz)
+	}
+	next := rb.seq - uint64(min(rb.len, sz))
+	rb.unlock()
+
+	buf := make([]T, sz)
+	rounds := (sz + chunk - 1) / chunk
+
+	for round := 1; ; round++ {
+		rb.lock()
+
+		if rb.meta != nil {
+			rb.unlock()
+			return rb.Resize(sz)
+		}
+
+		// Values older than the oldest remaining value were dropped, and only
+		// the newest sz values are kept.
+		next = max(next, rb.seq-uint64(min(rb.len, sz)))
+
+		// Copy the next chunk, or everything that's left, if it fits, or if
+		// this is the last round.
+		n := rb.seq - next
+		final := n <= uint64(chunk) || round >= rounds
+		if !final {
+			n = uint64(chunk)
+		}
+		for seq := next; seq < next+n; seq++ {
+			buf[seq%uint64(sz)] = rb.buf[rb.index(int(rb.seq-1-seq))]
+		}
+		next += n
+
+		if !final {
+			rb.unlock()
+			if testHookResizeRound != nil {
+				testHookResizeRound()
+			}
+			continue
+		}
+
+		// Everything is copied, so swap in the new backing array. Slots which
+		// don't hold a kept value may still hold values copied in an earlier
+		// round, which have since been dropped, e.g. by Clear.
+		var zero T
+		fill, cur := min(rb.len, sz), int(rb.seq%uint64(sz))
+		for i := fill; i < sz; i++ {
+			j := cur - 1 - i
+			if j < 0 {
+				j += sz
+			}
+			buf[j] = zero
+		}
+		for i := fill; i < rb.len; i++ {
+			val := rb.buf[rb.index(i)]
+			dropped = append(dropped, val)
+			if rb.cost != nil {
+				rb.costTotal -= rb.cost(val)
+			}
+		}
+
+		rb.buf = buf
+		rb.mask = maskFor(sz)
+		rb.cur = cur
+		rb.len = fill
+		rb.changed()
+
+		rb.unlock()
+		return dropped
+	}
+}
+
+// testHookResizeRound, if non-nil, is called between the rounds of
+// ResizeIncremental, without the lock held.
+var testHookResizeRound func()
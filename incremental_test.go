@@ -0,0 +1,116 @@
+package rb_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferResizeIncremental(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](100)
+	for i := range 150 {
+		rb.Add(i)
+	}
+
+	// Shrinking drops the oldest values, newest first.
+	dropped := rb.ResizeIncremental(10, 3)
+	assertEqual(t, 90, len(dropped))
+	assertEqual(t, 139, dropped[0])
+	assertEqual(t, 50, dropped[89])
+	assertEqual(t, []int{149, 148, 147}, rb.Published()[:3])
+	assertEqual(t, 10, rb.Stats().Capacity)
+
+	// Growing, with concurrent adds, keeps every value in order.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 150; i < 1150; i++ {
+			rb.Add(i)
+		}
+	}()
+	for _, sz := range []int{500, 2000, 50, 1000} {
+		rb.ResizeIncremental(sz, 7)
+	}
+	wg.Wait()
+
+	vals := rb.Published()
+	assertEqual(t, 1149, vals[0])
+	for i := 1; i < len(vals); i++ {
+		if vals[i] != vals[i-1]-1 {
+			t.Fatalf("value %d: want %d, have %d", i, vals[i-1]-1, vals[i])
+		}
+	}
+	if err := rb.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRingBufferResizeIncrementalFallback(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](5)
+	rb.EnableTimestamps(nil) // tracks metadata
+	for i := range 5 {
+		rb.Add(i)
+	}
+
+	assertEqual(t, []int{1, 0}, rb.ResizeIncremental(3, 1))
+	assertEqual(t, []int{4, 3, 2}, rb.Published())
+}
+
+func TestRingBufferResizeIncrementalBusy(t *testing.T) {
+	// Not parallel, because of the hook.
+
+	buf := rb.NewRingBuffer[int](10)
+	for i := range 10 {
+		buf.Add(i)
+	}
+
+	// The writer adds more than one chunk between every round, but the resize
+	// still completes, after a bounded number of rounds.
+	var rounds int
+	defer rb.SetResizeRoundHook(func() {
+		rounds += 1
+		for range 3 {
+			buf.Add(100 + rounds)
+		}
+	})()
+	buf.ResizeIncremental(20, 2)
+	assertEqual(t, 9, rounds) // between 20/2 rounds
+	assertEqual(t, 20, buf.Stats().Capacity)
+	vals := buf.Published()
+	assertEqual(t, 10, len(vals)) // all the old ring buffer could hold
+	assertEqual(t, 109, vals[0])
+	if err := buf.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRingBufferResizeIncrementalClear(t *testing.T) {
+	// Not parallel, because of the hook.
+
+	buf := rb.NewRingBuffer[int](10)
+	for i := range 10 {
+		buf.Add(i + 1)
+	}
+
+	// Values copied before the ring buffer is cleared aren't kept.
+	var cleared bool
+	defer rb.SetResizeRoundHook(func() {
+		if !cleared {
+			cleared = true
+			buf.Clear()
+			buf.Add(100)
+		}
+	})()
+	buf.ResizeIncremental(20, 2)
+	assertEqual(t, []int{100}, buf.Published())
+
+	want := make([]int, 20)
+	want[10] = 100
+	assertEqual(t, want, rb.BackingArray(buf))
+}
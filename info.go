@@ -0,0 +1,29 @@
+package rb
+
+import "maps"
+
+// Info describes a ring buffer, for humans. It's set at construction, via
+// NewRingBufferWithInfo, and surfaced by Stats, String, and RenderHTML, so that
+// exported views of the ring buffer are self-describing.
+type Info struct {
+	Name        string            // short identifier, e.g. "request_latency"
+	Description string            // what the values are
+	Unit        string            // of the values, if numeric, e.g. "ms"
+	Labels      map[string]string // arbitrary key-value pairs
+}
+
+// NewRingBufferWithInfo is like NewRingBuffer, but attaches the given info to
+// the ring buffer.
+func NewRingBufferWithInfo[T any](sz int, info Info) *RingBuffer[T] {
+	rb := NewRingBuffer[T](sz)
+	rb.info = info
+	rb.info.Labels = maps.Clone(info.Labels)
+	return rb
+}
+
+// Info returns the info attached to the ring buffer at construction.
+func (rb *RingBuffer[T]) Info() Info {
+	info := rb.info
+	info.Labels = maps.Clone(rb.info.Labels)
+	return info
+}
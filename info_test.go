@@ -0,0 +1,32 @@
+package rb_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestInfo(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"service": "api"}
+	info := rb.Info{Name: "latency", Description: "Request latency.", Unit: "ms", Labels: labels}
+	buf := rb.NewRingBufferWithInfo[int](3, info)
+	buf.Add(12)
+
+	// The info is copied, so it's immutable.
+	labels["service"] = "changed"
+	info.Labels = map[string]string{"service": "api"}
+	assertEqual(t, info, buf.Info())
+	assertEqual(t, info, buf.Stats().Info)
+
+	assertEqual(t, "RingBuffer{name=latency len=1 cap=3 newest=12 oldest=12}", fmt.Sprint(buf))
+
+	var sb strings.Builder
+	assertEqual(t, error(nil), buf.RenderHTML(&sb, rb.HTMLOptions{}))
+	for _, want := range []string{"<title>latency</title>", "<p>Request latency.</p>", "<code>service=api</code>", "<th>value (ms)</th>"} {
+		assertEqual(t, true, strings.Contains(sb.String(), want))
+	}
+}
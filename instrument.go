@@ -0,0 +1,73 @@
+package rb
+
+import (
+	"time"
+)
+
+// Operation describes a completed call to Add, Walk, or Resize, see Instrument.
+type Operation struct {
+	Name string        // "Add", "Walk", or "Resize"
+	Wait time.Duration // time spent waiting to acquire the lock
+	Hold time.Duration // time spent holding the lock
+}
+
+// Instrument sets a function which is called after every Add, Walk, or Resize
+// completes, with the time that call spent waiting for, and holding, the lock.
+// Walk includes All and WalkChunks, and the hold time of a walk includes the
+// time spent in the walk function. The function is called without the lock
+// held. Pass nil to disable.
+//
+// Instrumentation reads the clock two or three times per operation, so it's
+// not free; when disabled, there's no overhead beyond the atomic load of flags
+// that Add does anyway.
+func (rb *RingBuffer[T]) Instrument(fn func(Operation)) {
+	if fn == nil {
+		rb.instrument.Store(nil)
+		rb.setFlag(flagInstrument, false)
+		return
+	}
+	rb.instrument.Store(&fn)
+	rb.setFlag(flagInstrument, true)
+}
+
+// opTimer measures a single operation, if instrumentation is enabled.
+type opTimer struct {
+	fn     func(Operation)
+	name   string
+	start  time.Time
+	locked time.Time
+}
+
+// startOp should be called before trying to acquire the lock.
+func (rb *RingBuffer[T]) startOp(name string) opTimer {
+	p := rb.instrument.Load()
+	if p == nil {
+		return opTimer{}
+	}
+	return opTimer{fn: *p, name: name, start: time.Now()}
+}
+
+// acquired should be called once the lock is acquired.
+func (t *opTimer) acquired() {
+	if t.fn == nil {
+		return
+	}
+	t.locked = time.Now()
+}
+
+// done should be called once the lock is released.
+func (t *opTimer) done() {
+	if t.fn == nil {
+		return
+	}
+
+	op := Operation{Name: t.name}
+	if t.locked.IsZero() {
+		op.Wait = time.Since(t.start)
+	} else {
+		op.Wait = t.locked.Sub(t.start)
+		op.Hold = time.Since(t.locked)
+	}
+
+	t.fn(op)
+}
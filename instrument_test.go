@@ -0,0 +1,46 @@
+package rb_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestInstrument(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mtx sync.Mutex
+		ops []rb.Operation
+	)
+
+	record := func(op rb.Operation) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		ops = append(ops, op)
+	}
+
+	rb := rb.NewRingBuffer[int](3)
+	rb.Instrument(record)
+
+	rb.Add(1)
+	rb.Add(2)
+	rb.Walk(func(int) error { return nil })
+	rb.Resize(5)
+
+	var names []string
+	for _, op := range ops {
+		names = append(names, op.Name)
+		if op.Wait < 0 || op.Hold < 0 {
+			t.Errorf("%s: negative duration: %+v", op.Name, op)
+		}
+	}
+	assertEqual(t, []string{"Add", "Add", "Walk", "Resize"}, names)
+
+	// Disabled.
+	ops = nil
+	rb.Instrument(nil)
+	rb.Add(4)
+	assertEqual(t, 0, len(ops))
+}
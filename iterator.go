@@ -0,0 +1,44 @@
+package rb
+
+// Iterator iterates over the values in a ring buffer, newest first, without
+// allocating, and without holding the lock between calls to Next. It's an
+// alternative to Walk and All for tight loops, and for callers that need to do
+// other work between values.
+//
+// An Iterator visits the values which were in the ring buffer when it was
+// created, and which are still in the ring buffer when they're reached. Values
+// added after it's created aren't visited, and values dropped before they're
+// reached are skipped. An Iterator isn't safe for concurrent use.
+type Iterator[T any] struct {
+	rb   *RingBuffer[T]
+	next uint64 // the next value has the largest sequence number less than this
+}
+
+// Iterator returns an iterator over the current values in the ring buffer.
+func (rb *RingBuffer[T]) Iterator() Iterator[T] {
+	rb.lock()
+	defer rb.unlock()
+
+	return Iterator[T]{rb: rb, next: rb.seq}
+}
+
+// Next returns the next value, and true, or a zero value and false when there
+// are no more values. Each call takes the lock briefly.
+func (it *Iterator[T]) Next() (val T, ok bool) {
+	if it.rb == nil || it.next == 0 {
+		return val, false
+	}
+
+	rb := it.rb
+	rb.lock()
+	defer rb.unlock()
+
+	i := rb.countSince(it.next)
+	if i >= rb.len {
+		it.next = 0
+		return val, false
+	}
+
+	it.next = rb.seqAt(i)
+	return rb.buf[rb.index(i)], true
+}
@@ -0,0 +1,52 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferIterator(t *testing.T) {
+	collect := func(it rb.Iterator[int], between func()) []int {
+		res := []int{}
+		for val, ok := it.Next(); ok; val, ok = it.Next() {
+			res = append(res, val)
+			between()
+		}
+		return res
+	}
+
+	var zero rb.Iterator[int]
+	assertEqual(t, []int{}, collect(zero, func() {}))
+
+	rb := rb.NewRingBuffer[int](4)
+	assertEqual(t, []int{}, collect(rb.Iterator(), func() {}))
+
+	for i := range 6 {
+		rb.Add(i)
+	}
+	assertEqual(t, []int{5, 4, 3, 2}, collect(rb.Iterator(), func() {}))
+
+	// Values added during iteration aren't visited, and values dropped before
+	// they're reached are skipped.
+	n := 6
+	assertEqual(t, []int{5, 4}, collect(rb.Iterator(), func() { rb.Add(n); n++ }))
+
+	// The lock isn't held between calls.
+	it := rb.Iterator()
+	it.Next()
+	rb.Resize(8)
+	it.Next()
+	assertEqual(t, 8, rb.Stats().Capacity)
+
+	// Nothing is allocated.
+	if raceEnabled {
+		return
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		it := rb.Iterator()
+		for _, ok := it.Next(); ok; _, ok = it.Next() {
+		}
+	})
+	assertEqual(t, 0.0, allocs)
+}
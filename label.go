@@ -0,0 +1,66 @@
+package rb
+
+import "slices"
+
+// maxLabels is the maximum number of labeled snapshots retained by a ring
+// buffer. When it's exceeded, the oldest label is discarded.
+const maxLabels = 16
+
+// label is a named snapshot, see Label.
+type label[T any] struct {
+	name string
+	vals []T // newest first
+	sz   int // capacity of the ring buffer when labeled
+}
+
+// Label captures a snapshot of the values in the ring buffer, and retains it
+// under the given name, replacing any existing snapshot with the same name.
+// The snapshot can be retrieved later via Snapshot, e.g. to compare the values
+// at deploy time with the current values. At most 16 labels are retained, and
+// when that's exceeded, the oldest label is discarded.
+func (rb *RingBuffer[T]) Label(name string) {
+	rb.lock()
+	defer rb.unlock()
+
+	vals := make([]T, rb.len)
+	rb.copy(vals)
+
+	rb.labels = slices.DeleteFunc(rb.labels, func(l label[T]) bool { return l.name == name })
+	if len(rb.labels) >= maxLabels {
+		rb.labels = slices.Delete(rb.labels, 0, len(rb.labels)-maxLabels+1)
+	}
+	rb.labels = append(rb.labels, label[T]{name: name, vals: vals, sz: len(rb.buf)})
+}
+
+// Snapshot returns a new ring buffer with the values captured by Label under
+// the given name, and the capacity the ring buffer had at the time. The
+// snapshot itself is immutable, so each call returns a new ring buffer, which
+// can be modified independently. If there's no such label, it returns false.
+func (rb *RingBuffer[T]) Snapshot(name string) (*RingBuffer[T], bool) {
+	rb.lock()
+	i := slices.IndexFunc(rb.labels, func(l label[T]) bool { return l.name == name })
+	if i < 0 {
+		rb.unlock()
+		return nil, false
+	}
+	l := rb.labels[i]
+	rb.unlock()
+
+	snapshot := NewRingBuffer[T](l.sz)
+	for _, val := range slices.Backward(l.vals) {
+		snapshot.add(val)
+	}
+	return snapshot, true
+}
+
+// Labels returns the names of the retained labels, oldest first.
+func (rb *RingBuffer[T]) Labels() []string {
+	rb.lock()
+	defer rb.unlock()
+
+	names := make([]string, len(rb.labels))
+	for i, l := range rb.labels {
+		names[i] = l.name
+	}
+	return names
+}
@@ -0,0 +1,46 @@
+package rb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestLabel(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](3)
+	buf.Add(1)
+	buf.Add(2)
+	buf.Label("deploy")
+
+	buf.Add(3)
+	buf.Add(4)
+
+	snapshot, ok := buf.Snapshot("deploy")
+	assertEqual(t, true, ok)
+	vals, _ := snapshot.Take(3)
+	assertEqual(t, []int{2, 1}, vals)
+
+	// Snapshots are independent of each other, and of the label.
+	snapshot.Add(99)
+	snapshot, _ = buf.Snapshot("deploy")
+	vals, _ = snapshot.Take(3)
+	assertEqual(t, []int{2, 1}, vals)
+	assertEqual(t, 3, snapshot.Stats().Capacity)
+
+	_, ok = buf.Snapshot("missing")
+	assertEqual(t, false, ok)
+
+	// Relabeling replaces the snapshot, and the number of labels is bounded.
+	buf.Label("deploy")
+	for i := range 20 {
+		buf.Label(fmt.Sprint(i))
+	}
+	labels := buf.Labels()
+	assertEqual(t, 16, len(labels))
+	assertEqual(t, "4", labels[0])
+	_, ok = buf.Snapshot("deploy")
+	assertEqual(t, false, ok)
+}
@@ -0,0 +1,63 @@
+package rb
+
+import (
+	"time"
+)
+
+// LatencyWindow records the durations of recent operations, and reports
+// quantiles over them.
+//
+// It's safe for concurrent use by multiple goroutines.
+type LatencyWindow struct {
+	now       func() time.Time
+	durations *RingBuffer[time.Duration]
+}
+
+// NewLatencyWindow returns a latency window which remembers the last n
+// durations. Durations are measured and timestamped with the given clock, or
+// time.Now if it's nil.
+func NewLatencyWindow(n int, now func() time.Time) *LatencyWindow {
+	if now == nil {
+		now = time.Now
+	}
+
+	durations := NewRingBuffer[time.Duration](n)
+	durations.EnableTimestamps(now)
+
+	return &LatencyWindow{
+		now:       now,
+		durations: durations,
+	}
+}
+
+// Observe records a duration.
+func (w *LatencyWindow) Observe(d time.Duration) {
+	w.durations.Add(d)
+}
+
+// Track calls fn, records how long it took, and returns its error.
+//
+//	err := latencies.Track(func() error { return db.Ping() })
+func (w *LatencyWindow) Track(fn func() error) error {
+	begin := w.now()
+	err := fn()
+	w.Observe(w.now().Sub(begin))
+	return err
+}
+
+// Quantile returns the q-quantile of the recorded durations, see Quantile.
+func (w *LatencyWindow) Quantile(q float64) time.Duration {
+	return Quantile(w.durations, q)
+}
+
+// QuantileSince is like Quantile, but only considers durations recorded within
+// the last d.
+func (w *LatencyWindow) QuantileSince(q float64, d time.Duration) time.Duration {
+	w.durations.lock()
+	cutoff := w.durations.clock().Add(-d).UnixNano()
+	vals := make([]time.Duration, w.durations.countSinceTime(cutoff))
+	w.durations.copy(vals)
+	w.durations.unlock()
+
+	return quantile(vals, q)
+}
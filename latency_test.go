@@ -0,0 +1,40 @@
+package rb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestLatencyWindow(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	w := rb.NewLatencyWindow(100, clock.Now)
+
+	assertEqual(t, time.Duration(0), w.Quantile(0.5))
+
+	for i := 1; i <= 100; i++ {
+		w.Observe(time.Duration(i) * time.Millisecond)
+		clock.Advance(time.Second)
+	}
+
+	assertEqual(t, 50*time.Millisecond, w.Quantile(0.5))
+	assertEqual(t, 99*time.Millisecond, w.Quantile(0.99))
+	assertEqual(t, 100*time.Millisecond, w.Quantile(1))
+	assertEqual(t, 1*time.Millisecond, w.Quantile(0))
+
+	// Only the last 10 durations, 91ms through 100ms.
+	assertEqual(t, 95*time.Millisecond, w.QuantileSince(0.5, 10*time.Second))
+
+	errBoom := errors.New("boom")
+	err := w.Track(func() error {
+		clock.Advance(time.Hour)
+		return errBoom
+	})
+	assertEqual(t, true, errors.Is(err, errBoom))
+	assertEqual(t, time.Hour, w.Quantile(1))
+}
@@ -0,0 +1,47 @@
+package rb
+
+import (
+	"sync"
+	"time"
+)
+
+// SlidingWindowLimiter allows at most n events in any window of time. It keeps
+// the times of the n most recent allowed events in a ring buffer, and allows a
+// new event only if the oldest of those is at least a window old.
+//
+// It's safe for concurrent use by multiple goroutines.
+type SlidingWindowLimiter struct {
+	mtx    sync.Mutex // makes the check and the add atomic
+	n      int
+	window time.Duration
+	events *RingBuffer[time.Time]
+}
+
+// NewSlidingWindowLimiter returns a limiter that allows at most n events in any
+// window of time. If n <= 0, no events are allowed.
+func NewSlidingWindowLimiter(n int, window time.Duration) *SlidingWindowLimiter {
+	return &SlidingWindowLimiter{
+		n:      n,
+		window: window,
+		events: NewRingBuffer[time.Time](max(0, n)),
+	}
+}
+
+// Allow reports whether an event may happen at the given time, and if so,
+// records it. Times should be non-decreasing across calls.
+func (l *SlidingWindowLimiter) Allow(now time.Time) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.n <= 0 {
+		return false
+	}
+
+	_, oldest, count := l.events.Overview()
+	if count >= l.n && now.Sub(oldest) < l.window {
+		return false
+	}
+
+	l.events.Add(now)
+	return true
+}
@@ -0,0 +1,29 @@
+package rb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	l := rb.NewSlidingWindowLimiter(3, time.Minute)
+
+	assertEqual(t, true, l.Allow(at(0)))
+	assertEqual(t, true, l.Allow(at(10*time.Second)))
+	assertEqual(t, true, l.Allow(at(20*time.Second)))
+	assertEqual(t, false, l.Allow(at(30*time.Second)))
+	assertEqual(t, false, l.Allow(at(59*time.Second)))
+	assertEqual(t, true, l.Allow(at(60*time.Second))) // first event left the window
+	assertEqual(t, false, l.Allow(at(65*time.Second)))
+	assertEqual(t, true, l.Allow(at(70*time.Second)))
+
+	none := rb.NewSlidingWindowLimiter(0, time.Minute)
+	assertEqual(t, false, none.Allow(at(0)))
+}
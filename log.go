@@ -0,0 +1,143 @@
+package rb
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LogEntry is a log entry mirrored into ring buffers by a LogMirror. It's
+// independent of any logging package, so adapters for different loggers can
+// share the same ring buffers, and the same recent-logs view.
+type LogEntry struct {
+	Time    time.Time
+	Level   string
+	Logger  string
+	Message string
+	Fields  map[string]any
+}
+
+// LogMirror mirrors log entries into a set of ring buffers, with a category for
+// each entry chosen by a key function, e.g. LogByLevel or LogByLogger. It's the
+// common core of log adapters: NewSlogHandler adapts it to log/slog, and other
+// loggers need only a thin adapter which converts their entries and calls
+// Mirror. Adapters for zap and logrus are provided by the separate modules
+// github.com/peterbourgon/rb/rbzap and github.com/peterbourgon/rb/rblogrus, so
+// this package doesn't depend on them.
+//
+// It's safe for concurrent use by multiple goroutines.
+type LogMirror struct {
+	rbs *RingBuffers[LogEntry]
+	key func(LogEntry) string
+}
+
+// NewLogMirror returns a log mirror which adds entries to the ring buffers,
+// in the category returned by key. If key is nil, LogByLevel is used.
+func NewLogMirror(rbs *RingBuffers[LogEntry], key func(LogEntry) string) *LogMirror {
+	if key == nil {
+		key = LogByLevel
+	}
+	return &LogMirror{rbs: rbs, key: key}
+}
+
+// Mirror adds the entry to the ring buffer for its category.
+func (m *LogMirror) Mirror(e LogEntry) {
+	m.rbs.GetOrCreate(m.key(e)).Add(e)
+}
+
+// LogByLevel categorizes log entries by level.
+func LogByLevel(e LogEntry) string {
+	return e.Level
+}
+
+// LogByLogger categorizes log entries by logger name, or "default" for entries
+// without one.
+func LogByLogger(e LogEntry) string {
+	if e.Logger == "" {
+		return "default"
+	}
+	return e.Logger
+}
+
+// NewSlogHandler returns a slog.Handler which mirrors records at or above the
+// given level into the log mirror. Attributes in groups are flattened, with
+// keys qualified by the group names, e.g. "request.id". The logger name of
+// each entry is the value of the attribute "logger", if any, which is
+// otherwise kept as a field.
+func NewSlogHandler(m *LogMirror, level slog.Leveler) slog.Handler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &slogHandler{mirror: m, level: level}
+}
+
+type slogHandler struct {
+	mirror *LogMirror
+	level  slog.Leveler
+	prefix string // qualifies attribute keys, from WithGroup
+	attrs  []slog.Attr
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	e := LogEntry{
+		Time:    r.Time,
+		Level:   r.Level.String(),
+		Message: r.Message,
+		Fields:  map[string]any{},
+	}
+	for _, a := range h.attrs {
+		addSlogAttr(e.Fields, "", a) // already qualified
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(e.Fields, h.prefix, a)
+		return true
+	})
+	if logger, ok := e.Fields["logger"].(string); ok {
+		e.Logger = logger
+		delete(e.Fields, "logger")
+	}
+
+	h.mirror.Mirror(e)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// addSlogAttr adds the attribute to fields, flattening groups.
+func addSlogAttr(fields map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	switch {
+	case a.Equal(slog.Attr{}):
+		return
+	case v.Kind() == slog.KindGroup:
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addSlogAttr(fields, prefix, ga)
+		}
+	default:
+		fields[prefix+a.Key] = v.Any()
+	}
+}
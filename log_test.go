@@ -0,0 +1,50 @@
+package rb_test
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestSlogHandler(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[rb.LogEntry](10)
+	logger := slog.New(rb.NewSlogHandler(rb.NewLogMirror(rbs, nil), slog.LevelInfo))
+
+	logger.Debug("ignored")
+	logger.Info("hello", "n", 1)
+	logger.With("logger", "http").WithGroup("req").Warn("slow", "id", "abc", slog.Group("user", "name", "bob"))
+
+	assertEqual(t, []string{"INFO", "WARN"}, slices.Sorted(maps.Keys(rbs.GetAll())))
+
+	entries, _ := rbs.GetOrCreate("WARN").Take(10)
+	assertEqual(t, 1, len(entries))
+	e := entries[0]
+	assertEqual(t, true, time.Since(e.Time) < time.Minute)
+	e.Time = time.Time{}
+	assertEqual(t, rb.LogEntry{
+		Level:   "WARN",
+		Logger:  "http",
+		Message: "slow",
+		Fields:  map[string]any{"req.id": "abc", "req.user.name": "bob"},
+	}, e)
+
+	entries, _ = rbs.GetOrCreate("INFO").Take(10)
+	assertEqual(t, map[string]any{"n": int64(1)}, entries[0].Fields)
+}
+
+func TestLogMirror(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[rb.LogEntry](10)
+	m := rb.NewLogMirror(rbs, rb.LogByLogger)
+	m.Mirror(rb.LogEntry{Logger: "db", Message: "a"})
+	m.Mirror(rb.LogEntry{Message: "b"})
+
+	assertEqual(t, []string{"db", "default"}, slices.Sorted(maps.Keys(rbs.GetAll())))
+}
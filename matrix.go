@@ -0,0 +1,97 @@
+package rb
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// RingBuffersMatrix collects ring buffers by two levels of string keys, group
+// and category, e.g. tenant and endpoint. Each group is a RingBuffers, so
+// operations can be applied to a whole group at once.
+type RingBuffersMatrix[T any] struct {
+	mtx    sync.Mutex
+	sz     int
+	groups map[string]*RingBuffers[T]
+}
+
+// NewRingBuffersMatrix returns an empty matrix of ring buffers, each of which
+// will have a maximum size of sz, or 1, whichever is greater.
+func NewRingBuffersMatrix[T any](sz int) *RingBuffersMatrix[T] {
+	return &RingBuffersMatrix[T]{
+		sz:     max(1, sz),
+		groups: map[string]*RingBuffers[T]{},
+	}
+}
+
+// GetOrCreate returns the ring buffer for the given group and category,
+// creating the group and the ring buffer if necessary.
+func (m *RingBuffersMatrix[T]) GetOrCreate(group, category string) *RingBuffer[T] {
+	return m.GetOrCreateGroup(group).GetOrCreate(category)
+}
+
+// GetOrCreateGroup returns the ring buffers in the given group, creating the
+// group if necessary. New groups have the size of the matrix.
+func (m *RingBuffersMatrix[T]) GetOrCreateGroup(group string) *RingBuffers[T] {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	rbs, ok := m.groups[group]
+	if !ok {
+		rbs = NewRingBuffers[T](m.sz)
+		m.groups[group] = rbs
+	}
+	return rbs
+}
+
+// Groups returns the names of all groups, sorted.
+func (m *RingBuffersMatrix[T]) Groups() []string {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return slices.Sorted(maps.Keys(m.groups))
+}
+
+// ResizeGroup resizes all of the ring buffers in the group, including ring
+// buffers subsequently created in it, and returns the dropped values by
+// category. If the group doesn't exist, or sz <= 0, it's a no-op.
+func (m *RingBuffersMatrix[T]) ResizeGroup(group string, sz int) (dropped map[string][]T) {
+	m.mtx.Lock()
+	rbs, ok := m.groups[group]
+	m.mtx.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return rbs.Resize(sz)
+}
+
+// SnapshotGroup returns the values in each ring buffer in the group, newest
+// first, by category. Each ring buffer is read under its own lock, so the
+// snapshot is consistent per category, but not across categories.
+func (m *RingBuffersMatrix[T]) SnapshotGroup(group string) map[string][]T {
+	m.mtx.Lock()
+	rbs, ok := m.groups[group]
+	m.mtx.Unlock()
+
+	snapshot := map[string][]T{}
+	if !ok {
+		return snapshot
+	}
+	for category, rb := range rbs.GetAll() {
+		snapshot[category] = rb.values()
+	}
+	return snapshot
+}
+
+// DeleteGroup removes the group and all of its ring buffers from the matrix,
+// and returns true if it existed. Ring buffers already returned by GetOrCreate
+// remain usable, but are no longer part of the matrix.
+func (m *RingBuffersMatrix[T]) DeleteGroup(group string) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	_, ok := m.groups[group]
+	delete(m.groups, group)
+	return ok
+}
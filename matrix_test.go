@@ -0,0 +1,30 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBuffersMatrix(t *testing.T) {
+	t.Parallel()
+
+	m := rb.NewRingBuffersMatrix[int](3)
+	m.GetOrCreate("tenant-1", "http").Add(1)
+	m.GetOrCreate("tenant-1", "http").Add(2)
+	m.GetOrCreate("tenant-1", "grpc").Add(3)
+	m.GetOrCreate("tenant-2", "http").Add(4)
+
+	assertEqual(t, []string{"tenant-1", "tenant-2"}, m.Groups())
+	assertEqual(t, map[string][]int{"http": {2, 1}, "grpc": {3}}, m.SnapshotGroup("tenant-1"))
+
+	dropped := m.ResizeGroup("tenant-1", 1)
+	assertEqual(t, map[string][]int{"http": {1}, "grpc": nil}, dropped)
+	assertEqual(t, 1, m.GetOrCreate("tenant-1", "new").Stats().Capacity)
+	assertEqual(t, 3, m.GetOrCreate("tenant-2", "new").Stats().Capacity)
+
+	assertEqual(t, true, m.DeleteGroup("tenant-1"))
+	assertEqual(t, false, m.DeleteGroup("tenant-1"))
+	assertEqual(t, []string{"tenant-2"}, m.Groups())
+	assertEqual(t, map[string][]int{}, m.SnapshotGroup("tenant-1"))
+}
@@ -0,0 +1,55 @@
+package rb
+
+import "slices"
+
+// WalkMerged calls fn for every value in the given ring buffers, in the order
+// defined by less, e.g. by timestamps embedded in the values. Values which are
+// equal remain in the order of the ring buffers as given, and oldest first
+// within each ring buffer.
+//
+// The ring buffers are locked together, in the same canonical order as Tx, and
+// copied, so the walk sees a consistent view across all of them, e.g. a
+// response is never seen without its request. The locks are released before
+// fn is called, so fn may call methods on the ring buffers. If fn panics, the
+// panic is recovered and returned as a *PanicError.
+func WalkMerged[T any](less func(a, b T) bool, fn func(T) error, bufs ...*RingBuffer[T]) (err error) {
+	defer recoverPanic(&err)
+
+	txbufs := make([]txBuffer, len(bufs))
+	for i, rb := range bufs {
+		txbufs[i] = rb
+	}
+
+	var vals []T
+	Tx(txbufs...).Do(func() {
+		seen := map[*RingBuffer[T]]bool{}
+		for _, rb := range bufs {
+			if seen[rb] {
+				continue
+			}
+			seen[rb] = true
+
+			for _, segment := range rb.segments() {
+				vals = append(vals, segment...)
+			}
+		}
+	})
+
+	slices.SortStableFunc(vals, func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for _, val := range vals {
+		if err := fn(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package rb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestWalkMerged(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		At   int
+		What string
+	}
+	requests := rb.NewRingBuffer[event](5)
+	responses := rb.NewRingBuffer[event](5)
+	requests.Add(event{1, "req a"})
+	requests.Add(event{3, "req b"})
+	responses.Add(event{2, "res a"})
+	responses.Add(event{3, "res b"})
+
+	less := func(a, b event) bool { return a.At < b.At }
+
+	var have []string
+	err := rb.WalkMerged(less, func(e event) error {
+		have = append(have, e.What)
+		return nil
+	}, requests, responses, requests)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []string{"req a", "res a", "req b", "res b"}, have)
+
+	errStop := errors.New("stop")
+	have = have[:0]
+	err = rb.WalkMerged(less, func(e event) error {
+		have = append(have, e.What)
+		return errStop
+	}, responses, requests)
+	assertEqual(t, true, errors.Is(err, errStop))
+	assertEqual(t, []string{"req a"}, have)
+
+	var pe *rb.PanicError
+	err = rb.WalkMerged(less, func(event) error { panic("boom") }, requests)
+	assertEqual(t, true, errors.As(err, &pe))
+}
@@ -0,0 +1,118 @@
+package rb
+
+import (
+	"sort"
+)
+
+// Every value added to a ring buffer is assigned a sequence number, starting
+// at 0, and increasing by 1 with each add. Normally, the values in the ring
+// buffer are the most recently added values, and their sequence numbers can be
+// computed from their position. But if values can be removed from the middle
+// of the ring buffer, e.g. by an eviction policy, then sequence numbers have to
+// be tracked explicitly, per value. That's done in meta, which is parallel to
+// buf, and which also holds any other per-value metadata. It's nil until some
+// feature requires it, so the common case pays nothing.
+type meta struct {
+	seq     uint64 // sequence number
+	time    int64  // UnixNano when added, if timestamps are enabled
+	expires int64  // UnixNano, or 0 for never, see AddTTL
+	pinned  bool   // skipped when overwriting, see Pin
+}
+
+// index returns the index in buf of the i'th newest value, where i=0 is the
+// newest value. It assumes the lock is held.
+func (rb *RingBuffer[T]) index(i int) int {
+	if rb.mask != 0 {
+		return (rb.cur - 1 - i) & rb.mask
+	}
+	cur := rb.cur - 1 - i
+	if cur < 0 {
+		cur += len(rb.buf)
+	}
+	return cur
+}
+
+// seqAt returns the sequence number of the i'th newest value. It assumes the
+// lock is held.
+func (rb *RingBuffer[T]) seqAt(i int) uint64 {
+	if rb.meta != nil {
+		return rb.meta[rb.index(i)].seq
+	}
+	return rb.seq - 1 - uint64(i)
+}
+
+// countSince returns the number of values in the ring buffer with sequence
+// numbers greater than or equal to seq. It assumes the lock is held.
+func (rb *RingBuffer[T]) countSince(seq uint64) int {
+	if rb.meta != nil {
+		return sort.Search(rb.len, func(i int) bool { return rb.seqAt(i) < seq })
+	}
+	if seq >= rb.seq {
+		return 0
+	}
+	return int(min(rb.seq-seq, uint64(rb.len)))
+}
+
+// trackMeta starts tracking per-value metadata. It assumes the lock is held.
+func (rb *RingBuffer[T]) trackMeta() {
+	if rb.meta != nil {
+		return
+	}
+
+	rb.meta = make([]meta, len(rb.buf))
+	rb.setFlag(flagMeta, true)
+	for i := range rb.len {
+		rb.meta[rb.index(i)].seq = rb.seq - 1 - uint64(i)
+	}
+}
+
+// remove the i'th newest value from the ring buffer, shifting older values to
+// fill the gap, and return it. It assumes the lock is held.
+func (rb *RingBuffer[T]) remove(i int) T {
+	val := rb.buf[rb.index(i)]
+	rb.unpin(rb.index(i))
+
+	for ; i < rb.len-1; i++ {
+		dst, src := rb.index(i), rb.index(i+1)
+		rb.buf[dst] = rb.buf[src]
+		if rb.meta != nil {
+			rb.meta[dst] = rb.meta[src]
+		}
+	}
+
+	var zero T
+	rb.buf[rb.index(rb.len-1)] = zero
+	rb.len -= 1
+
+	return val
+}
+
+// compact removes every value for which keep returns false, given the index of
+// the value in buf, shifting older values to fill the gaps. It returns the
+// number of removed values. It assumes the lock is held.
+func (rb *RingBuffer[T]) compact(keep func(index int) bool) int {
+	var n int
+	for i := range rb.len {
+		src := rb.index(i)
+		if !keep(src) {
+			rb.unpin(src)
+			continue
+		}
+		if dst := rb.index(n); dst != src {
+			rb.buf[dst] = rb.buf[src]
+			if rb.meta != nil {
+				rb.meta[dst] = rb.meta[src]
+			}
+		}
+		n += 1
+	}
+
+	var zero T
+	for i := n; i < rb.len; i++ {
+		rb.buf[rb.index(i)] = zero
+	}
+
+	removed := rb.len - n
+	rb.len = n
+	return removed
+}
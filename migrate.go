@@ -0,0 +1,79 @@
+package rb
+
+import (
+	"fmt"
+)
+
+// Migrator is an optional interface for a Codec, which versions the encoding of
+// values, so that values encoded by an older version can still be decoded, e.g.
+// by Load, after the value type has changed.
+type Migrator interface {
+	// Version returns the current version of the encoding.
+	Version() uint64
+
+	// Migrate converts data encoded by the given older version to the current
+	// version.
+	Migrate(data []byte, from uint64) ([]byte, error)
+}
+
+// VersionedCodec wraps a Codec with a version, and migrations from older
+// versions. Construct it with NewVersionedCodec.
+type VersionedCodec[T any] struct {
+	codec      Codec[T]
+	version    uint64
+	migrations map[uint64]func([]byte) ([]byte, error)
+}
+
+var (
+	_ Codec[int] = (*VersionedCodec[int])(nil)
+	_ Migrator   = (*VersionedCodec[int])(nil)
+)
+
+// NewVersionedCodec returns a codec which encodes and decodes values with the
+// given codec, and identifies the encoding as the given version.
+func NewVersionedCodec[T any](codec Codec[T], version uint64) *VersionedCodec[T] {
+	return &VersionedCodec[T]{
+		codec:      codec,
+		version:    version,
+		migrations: map[uint64]func([]byte) ([]byte, error){},
+	}
+}
+
+// RegisterMigration registers a function which converts data encoded by
+// version from to version from+1. Migrations are chained, so there must be a
+// migration registered for every version between the oldest version that
+// should be supported and the current version.
+func (c *VersionedCodec[T]) RegisterMigration(from uint64, migrate func([]byte) ([]byte, error)) {
+	c.migrations[from] = migrate
+}
+
+// Encode implements Codec.
+func (c *VersionedCodec[T]) Encode(val T) ([]byte, error) {
+	return c.codec.Encode(val)
+}
+
+// Decode implements Codec.
+func (c *VersionedCodec[T]) Decode(data []byte) (T, error) {
+	return c.codec.Decode(data)
+}
+
+// Version implements Migrator.
+func (c *VersionedCodec[T]) Version() uint64 {
+	return c.version
+}
+
+// Migrate implements Migrator.
+func (c *VersionedCodec[T]) Migrate(data []byte, from uint64) ([]byte, error) {
+	for v := from; v < c.version; v++ {
+		migrate, ok := c.migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration from version %d", v)
+		}
+
+		var err error
+		if data, err = migrate(data); err != nil {
+			return nil, fmt.Errorf("migrate from version %d: %w", v, err)
+		}
+	}
+	return data, nil
+}
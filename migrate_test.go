@@ -0,0 +1,68 @@
+package rb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestVersionedCodecMigrations(t *testing.T) {
+	t.Parallel()
+
+	type userV1 struct {
+		Name string
+	}
+
+	type userV3 struct {
+		FullName string
+		Admin    bool
+	}
+
+	// Save with version 1 of the app.
+	old := rb.NewRingBuffer[userV1](3)
+	old.Add(userV1{Name: "alice"})
+	old.Add(userV1{Name: "bob"})
+	var snapshot bytes.Buffer
+	assertEqual(t, error(nil), old.Save(&snapshot, rb.NewVersionedCodec[userV1](rb.JSONCodec[userV1]{}, 1)))
+
+	// Load with version 3 of the app, which has evolved the value type twice.
+	codec := rb.NewVersionedCodec[userV3](rb.JSONCodec[userV3]{}, 3)
+	codec.RegisterMigration(1, func(data []byte) ([]byte, error) {
+		var v1 map[string]any
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]any{"FullName": v1["Name"]})
+	})
+	codec.RegisterMigration(2, func(data []byte) ([]byte, error) {
+		var v2 map[string]any
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return nil, err
+		}
+		v2["Admin"] = v2["FullName"] == "alice"
+		return json.Marshal(v2)
+	})
+
+	current := rb.NewRingBuffer[userV3](3)
+	assertEqual(t, error(nil), current.Load(bytes.NewReader(snapshot.Bytes()), codec))
+	vals, _ := current.Take(3)
+	assertEqual(t, []userV3{{FullName: "bob"}, {FullName: "alice", Admin: true}}, vals)
+
+	// Missing migrations, newer snapshots, and unversioned codecs all fail.
+	missing := rb.NewVersionedCodec[userV3](rb.JSONCodec[userV3]{}, 3)
+	missing.RegisterMigration(2, func(data []byte) ([]byte, error) { return data, nil })
+	if err := current.Load(bytes.NewReader(snapshot.Bytes()), missing); err == nil {
+		t.Errorf("want error for missing migration")
+	}
+
+	older := rb.NewVersionedCodec[userV3](rb.JSONCodec[userV3]{}, 0)
+	if err := current.Load(bytes.NewReader(snapshot.Bytes()), older); err == nil {
+		t.Errorf("want error for newer snapshot")
+	}
+
+	if err := current.Load(bytes.NewReader(snapshot.Bytes()), rb.JSONCodec[userV3]{}); err == nil {
+		t.Errorf("want error for unversioned codec")
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !rbdebug
+
+package rb
+
+// debugValidation enables validation after every mutation, see Validate.
+const debugValidation = false
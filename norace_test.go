@@ -0,0 +1,5 @@
+//go:build !race
+
+package rb_test
+
+const raceEnabled = false
@@ -0,0 +1,160 @@
+package rb
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Number is a constraint for numeric types, used by numeric helpers like
+// SumSince.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Integer is a constraint for integer types, used by DeltaRingBuffer.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// SumSince returns the sum of the values added to the ring buffer at or after
+// t, with the same semantics as CountSince. This is a function rather than a
+// method, because methods can't further constrain their type parameters.
+func SumSince[T Number](rb *RingBuffer[T], t time.Time) T {
+	rb.lock()
+	defer rb.unlock()
+
+	var sum T
+	for i := range rb.countSinceTime(t.UnixNano()) {
+		sum += rb.buf[rb.index(i)]
+	}
+	return sum
+}
+
+// Quantile returns the q-quantile of the values in the ring buffer, where q is
+// between 0 and 1, e.g. 0.99 for the 99th percentile, using the nearest-rank
+// method. If the ring buffer is empty, it returns 0.
+func Quantile[T Number](rb *RingBuffer[T], q float64) T {
+	rb.lock()
+	vals := make([]T, rb.len)
+	rb.copy(vals)
+	rb.unlock()
+
+	return quantile(vals, q)
+}
+
+// quantile sorts vals in place, and returns the q-quantile.
+func quantile[T Number](vals []T, q float64) T {
+	if len(vals) == 0 {
+		return 0
+	}
+
+	slices.Sort(vals)
+
+	rank := int(math.Ceil(min(max(q, 0), 1) * float64(len(vals))))
+	return vals[max(rank-1, 0)]
+}
+
+// Deltas returns the differences between consecutive values in the ring buffer,
+// newest first, so the first delta is the newest value minus the value before
+// it. There's one fewer delta than values. It's useful to turn raw counter
+// values into increments. Decreasing values, e.g. counter resets, produce
+// negative deltas, which overflow for unsigned types.
+func Deltas[T Number](rb *RingBuffer[T]) []T {
+	rb.lock()
+	defer rb.unlock()
+
+	deltas := make([]T, max(0, rb.len-1))
+	for i := range deltas {
+		deltas[i] = rb.buf[rb.index(i)] - rb.buf[rb.index(i+1)]
+	}
+	return deltas
+}
+
+// Derivative returns the rates of change between consecutive values in the ring
+// buffer, per the given duration, newest first, e.g. to turn raw counter values
+// into per-second rates. Rates are computed from the timestamps of the values,
+// so timestamps must be enabled. Pairs of values without timestamps, or with
+// the same timestamp, are skipped. If timestamps aren't enabled, it returns no
+// rates.
+func Derivative[T Number](rb *RingBuffer[T], per time.Duration) []float64 {
+	rb.lock()
+	defer rb.unlock()
+
+	rates := []float64{}
+	if rb.now == nil {
+		return rates
+	}
+
+	for i := range max(0, rb.len-1) {
+		a, b := rb.index(i), rb.index(i+1)
+		t1, t0 := rb.meta[a].time, rb.meta[b].time
+		if t0 == 0 || t1 <= t0 {
+			continue
+		}
+		dv := float64(rb.buf[a]) - float64(rb.buf[b])
+		rates = append(rates, dv/float64(t1-t0)*float64(per))
+	}
+	return rates
+}
+
+// Sum returns the sum of all values in the ring buffer. It iterates directly
+// over the contiguous segments of the backing array, without a callback per
+// value, so it's much faster than Walk for large buffers. Since generic code is
+// compiled separately for each underlying numeric type, the loops are as tight
+// as hand-written ones.
+func Sum[T Number](rb *RingBuffer[T]) T {
+	rb.lock()
+	defer rb.unlock()
+
+	var sum T
+	for _, segment := range rb.segments() {
+		for _, v := range segment {
+			sum += v
+		}
+	}
+	return sum
+}
+
+// Min returns the smallest value in the ring buffer, and true, or false if it's
+// empty. Like Sum, it iterates directly over the backing array.
+func Min[T Number](rb *RingBuffer[T]) (T, bool) {
+	rb.lock()
+	defer rb.unlock()
+
+	if rb.len == 0 {
+		var zero T
+		return zero, false
+	}
+
+	m := rb.buf[rb.index(0)]
+	for _, segment := range rb.segments() {
+		for _, v := range segment {
+			m = min(m, v)
+		}
+	}
+	return m, true
+}
+
+// Max returns the largest value in the ring buffer, and true, or false if it's
+// empty. Like Sum, it iterates directly over the backing array.
+func Max[T Number](rb *RingBuffer[T]) (T, bool) {
+	rb.lock()
+	defer rb.unlock()
+
+	if rb.len == 0 {
+		var zero T
+		return zero, false
+	}
+
+	m := rb.buf[rb.index(0)]
+	for _, segment := range rb.segments() {
+		for _, v := range segment {
+			m = max(m, v)
+		}
+	}
+	return m, true
+}
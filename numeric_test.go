@@ -0,0 +1,119 @@
+package rb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestSumSince(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	start := clock.Now()
+
+	vals := rb.NewRingBuffer[float64](100)
+	vals.EnableTimestamps(clock.Now)
+
+	for i := 1; i <= 10; i++ {
+		vals.Add(float64(i))
+		clock.Advance(10 * time.Second)
+	}
+
+	assertEqual(t, 55.0, rb.SumSince(vals, start))
+	assertEqual(t, 5.0+6+7+8+9+10, rb.SumSince(vals, clock.Now().Add(-time.Minute)))
+	assertEqual(t, 0.0, rb.SumSince(vals, clock.Now()))
+}
+
+func TestQuantile(t *testing.T) {
+	t.Parallel()
+
+	vals := rb.NewRingBuffer[int](10)
+	assertEqual(t, 0, rb.Quantile(vals, 0.5))
+
+	for _, i := range []int{15, 20, 35, 40, 50} {
+		vals.Add(i)
+	}
+
+	assertEqual(t, 15, rb.Quantile(vals, 0))
+	assertEqual(t, 15, rb.Quantile(vals, 0.05))
+	assertEqual(t, 20, rb.Quantile(vals, 0.3))
+	assertEqual(t, 20, rb.Quantile(vals, 0.4))
+	assertEqual(t, 35, rb.Quantile(vals, 0.5))
+	assertEqual(t, 50, rb.Quantile(vals, 1))
+	assertEqual(t, 50, rb.Quantile(vals, 2))
+}
+
+func TestDeltas(t *testing.T) {
+	t.Parallel()
+
+	vals := rb.NewRingBuffer[int](4)
+	assertEqual(t, []int{}, rb.Deltas(vals))
+
+	for _, i := range []int{10, 15, 25, 30, 42} {
+		vals.Add(i)
+	}
+
+	assertEqual(t, []int{12, 5, 10}, rb.Deltas(vals))
+}
+
+func TestDerivative(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	vals := rb.NewRingBuffer[uint64](10)
+	vals.Add(1) // no timestamp, so skipped
+	vals.EnableTimestamps(clock.Now)
+
+	for _, i := range []uint64{100, 130, 190, 190} {
+		clock.Advance(10 * time.Second)
+		vals.Add(i)
+	}
+
+	assertEqual(t, []float64{0, 6, 3}, rb.Derivative(vals, time.Second))
+	assertEqual(t, []float64{0, 360, 180}, rb.Derivative(vals, time.Minute))
+	assertEqual(t, []float64{}, rb.Derivative(rb.NewRingBuffer[int](10), time.Second))
+}
+
+func TestSumMinMax(t *testing.T) {
+	t.Parallel()
+
+	vals := rb.NewRingBuffer[int](4)
+	_, ok := rb.Min(vals)
+	assertEqual(t, false, ok)
+	_, ok = rb.Max(vals)
+	assertEqual(t, false, ok)
+	assertEqual(t, 0, rb.Sum(vals))
+
+	for _, i := range []int{-100, 7, 3, -2, 9, 5} { // wraps, dropping -100 and 7
+		vals.Add(i)
+	}
+
+	assertEqual(t, 15, rb.Sum(vals))
+	m, _ := rb.Min(vals)
+	assertEqual(t, -2, m)
+	m, _ = rb.Max(vals)
+	assertEqual(t, 9, m)
+}
+
+func BenchmarkSum(b *testing.B) {
+	vals := rb.NewRingBuffer[float64](1 << 20)
+	for i := range 1<<20 + 1<<19 {
+		vals.Add(float64(i))
+	}
+
+	b.Run("Sum", func(b *testing.B) {
+		for range b.N {
+			rb.Sum(vals)
+		}
+	})
+
+	b.Run("Walk", func(b *testing.B) {
+		for range b.N {
+			var sum float64
+			vals.Walk(func(v float64) error { sum += v; return nil })
+		}
+	})
+}
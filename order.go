@@ -0,0 +1,57 @@
+package rb
+
+// EnforceOrder makes the ring buffer maintain an order of values defined by the
+// before function, e.g. by comparing timestamps or sequence numbers embedded in
+// the values, so that readers can rely on it for binary search, or for merging
+// ring buffers. Each value added must not be ordered before the newest value.
+//
+// If tolerance is 0, values which would violate the order are rejected, and Add
+// returns them as dropped. If tolerance is greater than 0, a value which is
+// ordered before at most that many of the newest values is inserted into its
+// place among them, and only values which are further out of order are
+// rejected. Values for which before returns false in both directions keep the
+// order in which they were added. Sequence numbers and timestamps reflect the
+// position of values in the ring buffer, not the order in which they were
+// added.
+//
+// The order is only enforced for values added after it's enabled. Pass a nil
+// function to disable it.
+func (rb *RingBuffer[T]) EnforceOrder(before func(a, b T) bool, tolerance int) {
+	rb.lock()
+	defer rb.unlock()
+
+	rb.order = before
+	rb.setHooks()
+	rb.orderTolerance = max(0, tolerance)
+}
+
+// outOfOrder returns true if the value is ordered before more of the newest
+// values than the tolerance allows. It assumes the lock is held.
+func (rb *RingBuffer[T]) outOfOrder(val T) bool {
+	var n int
+	for n < rb.len && rb.order(val, rb.buf[rb.index(n)]) {
+		if n += 1; n > rb.orderTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// reorder moves the newest value back to its place in the order, within the
+// tolerance. It assumes the lock is held.
+func (rb *RingBuffer[T]) reorder() {
+	for i := 0; i < rb.orderTolerance && i+1 < rb.len; i++ {
+		a, b := rb.index(i), rb.index(i+1)
+		if !rb.order(rb.buf[a], rb.buf[b]) {
+			return
+		}
+
+		// Sequence numbers and timestamps stay in place, but expiry and
+		// pinning belong to the value.
+		rb.buf[a], rb.buf[b] = rb.buf[b], rb.buf[a]
+		if rb.meta != nil {
+			rb.meta[a].expires, rb.meta[b].expires = rb.meta[b].expires, rb.meta[a].expires
+			rb.meta[a].pinned, rb.meta[b].pinned = rb.meta[b].pinned, rb.meta[a].pinned
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestEnforceOrder(t *testing.T) {
+	t.Parallel()
+
+	before := func(a, b int) bool { return a < b }
+
+	t.Run("reject", func(t *testing.T) {
+		t.Parallel()
+
+		buf := rb.NewRingBuffer[int](5)
+		buf.EnforceOrder(before, 0)
+		buf.Add(1)
+		buf.Add(3)
+
+		dropped, ok := buf.Add(2)
+		assertEqual(t, true, ok)
+		assertEqual(t, 2, dropped)
+
+		buf.Add(3) // equal values are in order
+		rbtest.AssertContents[int](t, buf, []int{3, 3, 1})
+	})
+
+	t.Run("tolerance", func(t *testing.T) {
+		t.Parallel()
+
+		buf := rb.NewRingBuffer[int](5)
+		buf.EnforceOrder(before, 2)
+		for _, v := range []int{10, 20, 30, 25, 22} {
+			_, ok := buf.Add(v)
+			assertEqual(t, false, ok)
+		}
+		rbtest.AssertContents[int](t, buf, []int{30, 25, 22, 20, 10})
+
+		// 12 is before 4 of the newest values, which is too many.
+		dropped, ok := buf.Add(12)
+		assertEqual(t, true, ok)
+		assertEqual(t, 12, dropped)
+
+		// When full, the oldest value is still dropped.
+		dropped, ok = buf.Add(26)
+		assertEqual(t, true, ok)
+		assertEqual(t, 10, dropped)
+		rbtest.AssertContents[int](t, buf, []int{30, 26, 25, 22, 20})
+		assertEqual(t, error(nil), buf.Validate())
+	})
+}
@@ -0,0 +1,32 @@
+package rb
+
+// cacheLineSize is conservatively large: 64 bytes is typical, but adjacent
+// line prefetching on amd64, and larger lines on some arm64 CPUs, mean 128
+// bytes is the distance at which false sharing reliably stops.
+const cacheLineSize = 128
+
+// cacheLinePad is used to separate fields onto different cache lines.
+type cacheLinePad [cacheLineSize]byte
+
+// PaddedRingBuffer is a RingBuffer followed by padding, so that adjacent
+// elements of a slice of them, e.g. one per shard or CPU, never share a cache
+// line. Without padding, parallel adds to adjacent ring buffers contend on the
+// same cache line, even though they take different locks.
+//
+// Use NewPaddedRingBuffers to construct them, and take the address of the
+// embedded RingBuffer to use them. They must not be copied.
+type PaddedRingBuffer[T any] struct {
+	RingBuffer[T]
+	_ cacheLinePad
+}
+
+// NewPaddedRingBuffers returns n padded ring buffers, allocated contiguously,
+// each with size sz.
+func NewPaddedRingBuffers[T any](n, sz int) []PaddedRingBuffer[T] {
+	rbs := make([]PaddedRingBuffer[T], n)
+	for i := range rbs {
+		rbs[i].buf = make([]T, sz)
+		rbs[i].mask = maskFor(sz)
+	}
+	return rbs
+}
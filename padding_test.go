@@ -0,0 +1,48 @@
+package rb_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestNewPaddedRingBuffers(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewPaddedRingBuffers[int](4, 3)
+
+	var wg sync.WaitGroup
+	for i := range rbs {
+		wg.Add(1)
+		go func(rb *rb.RingBuffer[int]) {
+			defer wg.Done()
+			for j := range 10 {
+				rb.Add(i*100 + j)
+			}
+		}(&rbs[i].RingBuffer)
+	}
+	wg.Wait()
+
+	for i := range rbs {
+		vals, _ := rbs[i].Take(5)
+		assertEqual(t, []int{i*100 + 9, i*100 + 8, i*100 + 7}, vals)
+	}
+
+	// Adjacent ring buffers are separated by at least the padding.
+	assertEqual(t, true, unsafe.Sizeof(rbs[0])-unsafe.Sizeof(rbs[0].RingBuffer) >= 64)
+}
+
+func BenchmarkPaddedRingBuffers(b *testing.B) {
+	rbs := rb.NewPaddedRingBuffers[int](64, 1000)
+	var shard atomic.Int64
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		rb := &rbs[int(shard.Add(1)-1)%len(rbs)].RingBuffer
+		for i := 0; pb.Next(); i++ {
+			rb.Add(i)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package rb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// PanicRecord is a panic recovered by a PanicRecorder.
+type PanicRecord struct {
+	Time    time.Time
+	Message string // the panic value, formatted with fmt
+	Value   any    // the panic value
+	Stack   []byte // stack trace of the panicking goroutine
+}
+
+// PanicRecorder records recovered panics in a ring buffer, so the most recent
+// panics can be inspected after the fact, e.g. via RenderHTML, as a lightweight
+// in-process black box.
+type PanicRecorder struct {
+	rb *RingBuffer[PanicRecord]
+}
+
+// NewPanicRecorder returns a panic recorder which keeps the sz most recent
+// panics.
+func NewPanicRecorder(sz int) *PanicRecorder {
+	return &PanicRecorder{rb: NewRingBuffer[PanicRecord](sz)}
+}
+
+// Recover recovers a panic, if any, and records it. It must be deferred
+// directly, i.e. defer p.Recover(), since recover only works in a deferred
+// function. The panic doesn't propagate.
+func (p *PanicRecorder) Recover() {
+	if v := recover(); v != nil {
+		p.record(v)
+	}
+}
+
+// Go runs fn in a new goroutine, recording any panic, rather than crashing the
+// process.
+func (p *PanicRecorder) Go(fn func()) {
+	go func() {
+		defer p.Recover()
+		fn()
+	}()
+}
+
+// Middleware returns an HTTP handler which calls next, and records any panic,
+// responding with 500 Internal Server Error. As with net/http,
+// http.ErrAbortHandler isn't recorded, and continues to abort the handler.
+func (p *PanicRecorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			switch {
+			case v == nil:
+				return
+			case v == http.ErrAbortHandler:
+				panic(v)
+			}
+			p.record(v)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Panics returns the recorded panics, newest first.
+func (p *PanicRecorder) Panics() []PanicRecord {
+	return p.rb.values()
+}
+
+// RingBuffer returns the ring buffer of recorded panics.
+func (p *PanicRecorder) RingBuffer() *RingBuffer[PanicRecord] {
+	return p.rb
+}
+
+func (p *PanicRecorder) record(v any) {
+	p.rb.Add(PanicRecord{
+		Time:    time.Now(),
+		Message: fmt.Sprint(v),
+		Value:   v,
+		Stack:   debug.Stack(),
+	})
+}
+
+// SampleGoroutines adds the number of goroutines to the ring buffer at every
+// interval, until the context is done. Enable timestamps on the ring buffer to
+// correlate the samples with other events, e.g. recorded panics. If interval
+// isn't positive, 10s is used.
+func SampleGoroutines(ctx context.Context, rb *RingBuffer[int], interval time.Duration) error {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			rb.Add(runtime.NumGoroutine())
+		}
+	}
+}
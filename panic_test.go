@@ -0,0 +1,75 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestPanicRecorder(t *testing.T) {
+	t.Parallel()
+
+	p := rb.NewPanicRecorder(2)
+	assertEqual(t, 0, len(p.Panics()))
+
+	func() {
+		defer p.Recover()
+		panic("first")
+	}()
+
+	done := make(chan struct{})
+	p.Go(func() {
+		defer close(done)
+		panic("second")
+	})
+	<-done
+
+	h := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(r.URL.Path)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/third", nil))
+	assertEqual(t, http.StatusInternalServerError, rec.Code)
+
+	panics := p.Panics()
+	assertEqual(t, 2, len(panics))
+	assertEqual(t, "/third", panics[0].Message)
+	assertEqual(t, "second", panics[1].Message)
+	assertEqual(t, true, strings.Contains(string(panics[0].Stack), "TestPanicRecorder"))
+	assertEqual(t, true, time.Since(panics[0].Time) < time.Minute)
+
+	abort := p.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assertPanics(t, func() { abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)) })
+	assertEqual(t, 2, len(p.Panics()))
+}
+
+func TestSampleGoroutines(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](10)
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- rb.SampleGoroutines(ctx, buf, time.Millisecond) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, _, count := buf.Overview(); count >= 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	assertEqual(t, true, errors.Is(<-errc, context.Canceled))
+
+	newest, _, count := buf.Overview()
+	assertEqual(t, true, count >= 2)
+	assertEqual(t, true, newest > 0)
+}
@@ -0,0 +1,185 @@
+package rb
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// snapshotMagic identifies a snapshot written by Save.
+var snapshotMagic = []byte("rbsnap")
+
+// snapshotFormat is the version of the snapshot format written by Save.
+const snapshotFormat = 1
+
+// Save writes a snapshot of the values in the ring buffer to w, each encoded by
+// the codec. The values are copied under the lock, and encoded and written
+// after it's released. The snapshot can be restored with Load.
+//
+// The format is the magic string "rbsnap", followed by uvarints for the format
+// version, currently 1, the value version, and the number of values, followed
+// by each value, oldest first, as a uvarint length prefix and the encoded
+// bytes. The value version is 0, unless the codec implements Migrator, in
+// which case it's the codec's version.
+func (rb *RingBuffer[T]) Save(w io.Writer, codec Codec[T]) error {
+	rb.lock()
+	vals := make([]T, 0, rb.len)
+	for _, segment := range rb.segments() {
+		vals = append(vals, segment...)
+	}
+	rb.unlock()
+
+	bw := bufio.NewWriter(w)
+	var version uint64
+	if m, ok := codec.(Migrator); ok {
+		version = m.Version()
+	}
+
+	bw.Write(snapshotMagic)
+	bw.Write(binary.AppendUvarint(nil, snapshotFormat))
+	bw.Write(binary.AppendUvarint(nil, version))
+	bw.Write(binary.AppendUvarint(nil, uint64(len(vals))))
+	for i, val := range vals {
+		data, err := codec.Encode(val)
+		if err != nil {
+			return fmt.Errorf("encode value %d: %w", i, err)
+		}
+		bw.Write(binary.AppendUvarint(nil, uint64(len(data))))
+		bw.Write(data)
+	}
+	return bw.Flush()
+}
+
+// Load reads a snapshot written by Save from r, decodes each value with the
+// codec, and adds them to the ring buffer, oldest first. Values already in the
+// ring buffer are kept, unless they're dropped to make room. If the snapshot
+// can't be read completely, nothing is added.
+//
+// If the codec implements Migrator, and the snapshot was saved with an older
+// value version, then each value is migrated to the codec's version before
+// it's decoded.
+func (rb *RingBuffer[T]) Load(r io.Reader, codec Codec[T]) error {
+	vals, err := ReadSnapshot(r, codec)
+	if err != nil {
+		return err
+	}
+
+	rb.lock()
+	defer rb.unlock()
+
+	for _, val := range vals {
+		rb.add(val)
+	}
+	return nil
+}
+
+// ReadSnapshot reads a snapshot written by Save from r, and returns the decoded
+// values, oldest first. It's like Load, but doesn't need a ring buffer, which
+// is useful for inspecting a snapshot offline.
+func ReadSnapshot[T any](r io.Reader, codec Codec[T]) ([]T, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if !bytes.Equal(magic, snapshotMagic) {
+		return nil, errors.New("not a snapshot")
+	}
+
+	format, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("read format version: %w", err)
+	}
+	if format != snapshotFormat {
+		return nil, fmt.Errorf("unsupported snapshot format version %d", format)
+	}
+
+	version, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("read value version: %w", err)
+	}
+	migrator, _ := codec.(Migrator)
+	switch {
+	case migrator == nil && version != 0:
+		return nil, fmt.Errorf("snapshot has value version %d, but codec isn't versioned", version)
+	case migrator != nil && version > migrator.Version():
+		return nil, fmt.Errorf("snapshot has value version %d, newer than codec version %d", version, migrator.Version())
+	}
+
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("read count: %w", err)
+	}
+
+	var vals []T
+	for i := range count {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("read value %d: %w", i, err)
+		}
+		var data bytes.Buffer // grows as data is read, in case n is corrupt
+		if _, err := io.CopyN(&data, br, int64(n)); err != nil {
+			return nil, fmt.Errorf("read value %d: %w", i, err)
+		}
+		raw := data.Bytes()
+		if migrator != nil && version < migrator.Version() {
+			if raw, err = migrator.Migrate(raw, version); err != nil {
+				return nil, fmt.Errorf("migrate value %d: %w", i, err)
+			}
+		}
+		val, err := codec.Decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode value %d: %w", i, err)
+		}
+		vals = append(vals, val)
+	}
+
+	return vals, nil
+}
+
+// SaveEncrypted is like Save, but encrypts the snapshot with the given AEAD,
+// e.g. AES-GCM from crypto/cipher, using a random nonce. The snapshot is
+// buffered in memory, so it can be sealed as a whole. The key is managed by
+// the caller.
+func (rb *RingBuffer[T]) SaveEncrypted(w io.Writer, codec Codec[T], aead cipher.AEAD) error {
+	var buf bytes.Buffer
+	if err := rb.Save(&buf, codec); err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+
+	_, err := w.Write(aead.Seal(nonce, nonce, buf.Bytes(), snapshotMagic))
+	return err
+}
+
+// LoadEncrypted is like Load, for a snapshot written by SaveEncrypted with the
+// same AEAD and key. It returns an error if the snapshot can't be decrypted or
+// authenticated, in which case nothing is added.
+func (rb *RingBuffer[T]) LoadEncrypted(r io.Reader, codec Codec[T], aead cipher.AEAD) error {
+	sealed, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return errors.New("encrypted snapshot too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, snapshotMagic)
+	if err != nil {
+		return fmt.Errorf("decrypt snapshot: %w", err)
+	}
+
+	return rb.Load(bytes.NewReader(plaintext), codec)
+}
@@ -0,0 +1,76 @@
+package rb_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	src := rb.NewRingBuffer[string](3)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		src.Add(s)
+	}
+
+	var buf bytes.Buffer
+	assertEqual(t, error(nil), src.Save(&buf, rb.JSONCodec[string]{}))
+
+	dst := rb.NewRingBuffer[string](5)
+	dst.Add("z")
+	assertEqual(t, error(nil), dst.Load(bytes.NewReader(buf.Bytes()), rb.JSONCodec[string]{}))
+	vals, _ := dst.Take(10)
+	assertEqual(t, []string{"d", "c", "b", "z"}, vals)
+
+	// Truncated snapshots add nothing.
+	empty := rb.NewRingBuffer[string](5)
+	if err := empty.Load(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), rb.JSONCodec[string]{}); err == nil {
+		t.Errorf("want error for truncated snapshot")
+	}
+	if err := empty.Load(bytes.NewReader([]byte("garbage")), rb.JSONCodec[string]{}); err == nil {
+		t.Errorf("want error for garbage")
+	}
+	_, _, count := empty.Overview()
+	assertEqual(t, 0, count)
+}
+
+func TestRingBufferSaveLoadEncrypted(t *testing.T) {
+	t.Parallel()
+
+	newAEAD := func(key string) cipher.AEAD {
+		block, err := aes.NewCipher([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return aead
+	}
+
+	aead := newAEAD("0123456789abcdef")
+	codec := rb.JSONCodec[string]{}
+
+	src := rb.NewRingBuffer[string](3)
+	src.Add("secret payload")
+
+	var buf bytes.Buffer
+	assertEqual(t, error(nil), src.SaveEncrypted(&buf, codec, aead))
+	assertEqual(t, false, bytes.Contains(buf.Bytes(), []byte("secret")))
+
+	dst := rb.NewRingBuffer[string](3)
+	assertEqual(t, error(nil), dst.LoadEncrypted(bytes.NewReader(buf.Bytes()), codec, aead))
+	vals, _ := dst.Take(3)
+	assertEqual(t, []string{"secret payload"}, vals)
+
+	// The wrong key fails.
+	wrong := rb.NewRingBuffer[string](3)
+	if err := wrong.LoadEncrypted(bytes.NewReader(buf.Bytes()), codec, newAEAD("fedcba9876543210")); err == nil {
+		t.Errorf("want error for wrong key")
+	}
+}
@@ -0,0 +1,145 @@
+package rb
+
+// Pin configures the ring buffer to pin values as they're added, if pred
+// returns true for them. Pinned values stay in the ring buffer, and are seen by
+// readers as usual, but are skipped when the ring buffer overwrites values to
+// make room, so they survive longer than their neighbours, e.g. an error among
+// a flood of routine logs. They still count against the capacity, and can be
+// removed explicitly, e.g. by TrimOldest, Clear, or a Resize which shrinks the
+// ring buffer. An eviction policy, see SetEvictionPolicy, ignores pins.
+//
+// The number of pinned values is limited to fraction of the capacity of the
+// ring buffer, and once that limit is reached, further values aren't pinned,
+// so that the earliest pinned values survive. Calling Pin with a nil pred stops
+// pinning new values, except via AddPinned, but doesn't release existing
+// pinned values; use Unpin for that.
+func (rb *RingBuffer[T]) Pin(pred func(T) bool, fraction float64) {
+	rb.lock()
+	defer rb.unlock()
+
+	rb.trackMeta()
+	rb.pin = pred
+	rb.setHooks()
+	rb.pinFraction = min(max(0, fraction), 1)
+	rb.trimPins()
+}
+
+// AddPinned is like Add, but pins the value, if there's room, regardless of the
+// predicate given to Pin. The limit on pinned values is set by Pin, and is 0
+// until then, so Pin must be called first, though pred may be nil.
+func (rb *RingBuffer[T]) AddPinned(val T) (dropped T, ok bool) {
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.addPinned(val)
+}
+
+// addPinned is AddPinned, and assumes the lock is held.
+func (rb *RingBuffer[T]) addPinned(val T) (dropped T, ok bool) {
+	rb.trackMeta()
+
+	dropped, ok = rb.add(val)
+
+	// The add may have been rejected by an eviction policy.
+	if rb.len > 0 && rb.seqAt(0) == rb.seq-1 {
+		rb.tryPin(rb.index(0))
+	}
+
+	return dropped, ok
+}
+
+// Pinned returns all pinned values, newest first.
+func (rb *RingBuffer[T]) Pinned() []T {
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.pinnedValues(false)
+}
+
+// Unpin releases all pinned values, so they're overwritten as normal, and
+// returns them newest first. They stay in the ring buffer.
+func (rb *RingBuffer[T]) Unpin() []T {
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.pinnedValues(true)
+}
+
+// pinnedValues returns all pinned values, newest first, and unpins them if
+// release is true. It assumes the lock is held.
+func (rb *RingBuffer[T]) pinnedValues(release bool) []T {
+	vals := []T{}
+	if rb.meta == nil {
+		return vals
+	}
+
+	for i := range rb.len {
+		if m := &rb.meta[rb.index(i)]; m.pinned {
+			vals = append(vals, rb.buf[rb.index(i)])
+			m.pinned = !release
+		}
+	}
+	if release {
+		rb.pinned = 0
+	}
+	return vals
+}
+
+// pinLimit returns the max number of pinned values. It assumes the lock is
+// held.
+func (rb *RingBuffer[T]) pinLimit() int {
+	return int(rb.pinFraction * float64(len(rb.buf)))
+}
+
+// tryPin pins the value at the given index in buf, if there's room. It
+// assumes the lock is held, and that metadata is tracked.
+func (rb *RingBuffer[T]) tryPin(index int) {
+	if m := &rb.meta[index]; !m.pinned && rb.pinned < rb.pinLimit() {
+		m.pinned = true
+		rb.pinned += 1
+	}
+}
+
+// unpin unpins the value at the given index in buf, if it's pinned, e.g. as
+// it's removed. It assumes the lock is held.
+func (rb *RingBuffer[T]) unpin(index int) {
+	if rb.meta != nil && rb.meta[index].pinned {
+		rb.meta[index].pinned = false
+		rb.pinned -= 1
+	}
+}
+
+// trimPins recounts the pinned values, e.g. after a resize, and unpins the
+// newest of them, as necessary to get within the limit. It assumes the lock is
+// held.
+func (rb *RingBuffer[T]) trimPins() {
+	rb.pinned = 0
+	if rb.meta == nil {
+		return
+	}
+
+	for i := range rb.len {
+		if rb.meta[rb.index(i)].pinned {
+			rb.pinned += 1
+		}
+	}
+
+	for i := 0; i < rb.len && rb.pinned > rb.pinLimit(); i++ {
+		rb.unpin(rb.index(i))
+	}
+}
+
+// evictable returns the position, where 0 is the newest, of the oldest value
+// at or beyond the given position which isn't pinned, or of the oldest value,
+// if they're all pinned. It assumes the lock is held, and the ring buffer
+// isn't empty.
+func (rb *RingBuffer[T]) evictable(newest int) int {
+	if rb.pinned > 0 {
+		for i := rb.len - 1; i >= newest; i-- {
+			if !rb.meta[rb.index(i)].pinned {
+				return i
+			}
+		}
+	}
+	return rb.len - 1
+}
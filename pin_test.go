@@ -0,0 +1,110 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestPin(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](10)
+	rb.Pin(func(i int) bool { return i < 0 }, 0.2) // up to 2 pinned values
+
+	rb.Add(-1) // the triggering error
+	for i := range 20 {
+		rb.Add(i)
+	}
+
+	// Pinned values stay in the ring buffer, and count against its capacity.
+	assertEqual(t, []int{-1}, rb.Pinned())
+	vals, _ := rb.Take(10)
+	assertEqual(t, []int{19, 18, 17, 16, 15, 14, 13, 12, 11, -1}, vals)
+
+	rb.Add(-2)
+	rb.Add(-3)
+	for i := range 20 {
+		dropped, ok := rb.Add(i)
+		if dropped < 0 {
+			assertEqual(t, -3, dropped) // only room for -2
+			assertEqual(t, true, ok)
+		}
+	}
+	assertEqual(t, []int{-2, -1}, rb.Pinned())
+	vals, _ = rb.Take(10)
+	assertEqual(t, []int{19, 18, 17, 16, 15, 14, 13, 12, -2, -1}, vals)
+
+	// Unpinned values are overwritten as normal.
+	assertEqual(t, []int{-2, -1}, rb.Unpin())
+	assertEqual(t, []int{}, rb.Pinned())
+	dropped, _ := rb.Add(20)
+	assertEqual(t, -1, dropped)
+}
+
+func TestAddPinned(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](4)
+	rb.Pin(nil, 0.5) // up to 2 pinned values, only via AddPinned
+
+	rb.AddPinned(100)
+	rb.Add(-1) // not pinned, as there's no predicate
+	rb.AddPinned(200)
+	rb.AddPinned(300) // no room
+	for i := range 10 {
+		rb.Add(i)
+	}
+
+	assertEqual(t, []int{200, 100}, rb.Pinned())
+	vals, _ := rb.Take(4)
+	assertEqual(t, []int{9, 8, 200, 100}, vals)
+}
+
+func TestPinResize(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](10)
+	rb.Pin(func(i int) bool { return i < 0 }, 0.3) // up to 3 pinned values
+
+	for _, i := range []int{-1, -2, -3, 1, 2, 3, 4, 5, 6, 7, 8, 9} {
+		rb.Add(i)
+	}
+	assertEqual(t, []int{-3, -2, -1}, rb.Pinned())
+
+	// Shrinking drops the oldest values, pinned or not.
+	assertEqual(t, []int{3, -3, -2, -1}, rb.Resize(6))
+	assertEqual(t, []int{}, rb.Pinned())
+
+	rb.Resize(10)
+	rb.AddPinned(-4)
+	rb.AddPinned(-5)
+	vals, _ := rb.Take(8)
+	assertEqual(t, []int{-5, -4, 9, 8, 7, 6, 5, 4}, vals)
+
+	// Shrinking also unpins the newest pinned values beyond the new limit.
+	assertEqual(t, []int{7, 6, 5, 4}, rb.Resize(4))
+	assertEqual(t, []int{-4}, rb.Pinned())
+}
+
+func TestPinRemoved(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](4)
+	rb.Pin(func(i int) bool { return i < 0 }, 0.5) // up to 2 pinned values
+
+	for _, i := range []int{-1, -2, -3} {
+		rb.Add(i)
+	}
+	assertEqual(t, []int{-2, -1}, rb.Pinned())
+
+	// Removing pinned values makes room for more.
+	assertEqual(t, 1, rb.TrimOldest(0.25))
+	rb.Add(-4)
+	assertEqual(t, []int{-4, -2}, rb.Pinned())
+
+	rb.Clear()
+	rb.AddPinned(-5)
+	rb.AddPinned(-6)
+	assertEqual(t, []int{-6, -5}, rb.Pinned())
+}
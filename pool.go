@@ -0,0 +1,37 @@
+package rb
+
+// pooledSlice is a slice vended by TakePooled. Its release function is created
+// once, and reused, so that TakePooled doesn't allocate in the steady state.
+type pooledSlice[T any] struct {
+	vals    []T
+	release func()
+}
+
+// TakePooled is like Take, but the returned slice comes from a pool owned by
+// the ring buffer, and must be returned to it by calling release, exactly once,
+// when the caller is done with it. The slice must not be used, or retained, after
+// release is called. Once the pool is warm, TakePooled doesn't allocate, which
+// is useful for consumers that take snapshots at high frequency.
+func (rb *RingBuffer[T]) TakePooled(n int) (vals []T, release func()) {
+	p, _ := rb.pool.Get().(*pooledSlice[T])
+	if p == nil {
+		p = &pooledSlice[T]{}
+		p.release = func() {
+			clear(p.vals) // don't retain references to values
+			p.vals = p.vals[:0]
+			rb.pool.Put(p)
+		}
+	}
+
+	rb.lock()
+	defer rb.unlock()
+
+	n = min(max(0, n), rb.len)
+	if cap(p.vals) < n {
+		p.vals = make([]T, n)
+	}
+	p.vals = p.vals[:n]
+	rb.copy(p.vals)
+
+	return p.vals, p.release
+}
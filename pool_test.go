@@ -0,0 +1,55 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferTakePooled(t *testing.T) {
+	rb := rb.NewRingBuffer[int](5)
+	for i := range 7 {
+		rb.Add(i)
+	}
+
+	vals, release := rb.TakePooled(3)
+	assertEqual(t, []int{6, 5, 4}, vals)
+	release()
+
+	vals, release = rb.TakePooled(10)
+	assertEqual(t, []int{6, 5, 4, 3, 2}, vals)
+	release()
+
+	// Once the pool is warm, nothing is allocated. That can't be measured under
+	// the race detector, which makes sync.Pool drop items at random.
+	if raceEnabled {
+		return
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		_, release := rb.TakePooled(5)
+		release()
+	})
+	assertEqual(t, 0.0, allocs)
+}
+
+func BenchmarkRingBufferTake(b *testing.B) {
+	rb := rb.NewRingBuffer[int](100)
+	for i := range 100 {
+		rb.Add(i)
+	}
+
+	b.Run("Take", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			rb.Take(100)
+		}
+	})
+
+	b.Run("TakePooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, release := rb.TakePooled(100)
+			release()
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package rb
+
+import (
+	"math/bits"
+)
+
+// NewRingBufferPow2 is like NewRingBuffer, but rounds sz up to the next power
+// of two. Ring buffers whose size is a power of two compute positions in the
+// backing array with a bitmask rather than a branch, which makes Add, Walk, and
+// Copy slightly faster. That applies to any ring buffer whose size happens to
+// be a power of two, including after Resize, but this constructor makes sure.
+func NewRingBufferPow2[T any](sz int) *RingBuffer[T] {
+	if sz > 1 {
+		sz = 1 << bits.Len(uint(sz-1))
+	}
+	return NewRingBuffer[T](sz)
+}
+
+// maskFor returns the bitmask for a backing array of size sz, or 0 if sz isn't
+// a power of two greater than 1.
+func maskFor(sz int) int {
+	if sz <= 1 || sz&(sz-1) != 0 {
+		return 0
+	}
+	return sz - 1
+}
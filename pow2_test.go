@@ -0,0 +1,48 @@
+package rb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestNewRingBufferPow2(t *testing.T) {
+	t.Parallel()
+
+	for sz, want := range map[int]int{0: 0, 1: 1, 2: 2, 3: 4, 1000: 1024, 1024: 1024} {
+		assertEqual(t, want, rb.NewRingBufferPow2[int](sz).Stats().Capacity)
+	}
+
+	rb := rb.NewRingBufferPow2[int](3)
+	for i := range 10 {
+		rb.Add(i)
+	}
+	assertEqual(t, []int{9, 8, 7, 6}, rb.Published())
+	vals, _ := rb.Take(2)
+	assertEqual(t, []int{9, 8}, vals)
+
+	// Resizing to a size which isn't a power of two still works.
+	rb.Resize(3)
+	rb.Add(10)
+	assertEqual(t, []int{10, 9, 8}, rb.Published())
+	rb.Resize(8)
+	rb.Add(11)
+	assertEqual(t, []int{11, 10, 9, 8}, rb.Published())
+
+	if err := rb.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkRingBufferPow2(b *testing.B) {
+	for _, sz := range []int{1000, 1024} {
+		b.Run(fmt.Sprintf("sz=%d", sz), func(b *testing.B) {
+			rb := rb.NewRingBuffer[int](sz)
+			b.ReportAllocs()
+			for i := range b.N {
+				rb.Add(i)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package rb
+
+// PriorityRingBuffer is a fixed-size collection of recent values, each with a
+// priority. When it's full, adding a value evicts the lowest priority value,
+// and of those, the oldest; the new value itself is rejected if its priority is
+// lower than every existing value. This means high priority values outlive low
+// priority values in the same bounded space.
+//
+// Eviction is O(n) in the size of the buffer. It's safe for concurrent use by
+// multiple goroutines.
+type PriorityRingBuffer[T any] struct {
+	rb *RingBuffer[prioritized[T]]
+}
+
+type prioritized[T any] struct {
+	val      T
+	priority int
+}
+
+// NewPriorityRingBuffer returns an empty priority ring buffer of values of type
+// T, with a pre-allocated and fixed size as defined by sz.
+func NewPriorityRingBuffer[T any](sz int) *PriorityRingBuffer[T] {
+	rb := NewRingBuffer[prioritized[T]](sz)
+	rb.SetEvictionPolicy(EvictionFunc[prioritized[T]](evictLowestPriority[T]))
+	return &PriorityRingBuffer[T]{rb: rb}
+}
+
+func evictLowestPriority[T any](incoming prioritized[T], values View[prioritized[T]]) int {
+	victim := -1
+	lowest := incoming.priority
+	for i := range values.Len() {
+		if p := values.At(i).priority; p <= lowest {
+			victim, lowest = i, p // <= so the oldest of equals wins
+		}
+	}
+	return victim
+}
+
+// Add the value with the given priority. If a value was evicted, which may be
+// the given value, return it and true; otherwise, return a zero value and false.
+func (prb *PriorityRingBuffer[T]) Add(val T, priority int) (dropped T, ok bool) {
+	d, ok := prb.rb.Add(prioritized[T]{val, priority})
+	return d.val, ok
+}
+
+// Walk calls the given function for each value and its priority, starting with
+// the most recent value, and ending with the oldest value. It has the same
+// semantics as RingBuffer.Walk.
+func (prb *PriorityRingBuffer[T]) Walk(fn func(val T, priority int) error) error {
+	return prb.rb.Walk(func(p prioritized[T]) error {
+		return fn(p.val, p.priority)
+	})
+}
+
+// Take returns up to the n most recent values, newest first.
+func (prb *PriorityRingBuffer[T]) Take(n int) ([]T, error) {
+	ps, err := prb.rb.Take(n)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]T, len(ps))
+	for i, p := range ps {
+		vals[i] = p.val
+	}
+	return vals, nil
+}
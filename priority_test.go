@@ -0,0 +1,47 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestPriorityRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	const (
+		debug = 0
+		crash = 9
+	)
+
+	prb := rb.NewPriorityRingBuffer[string](3)
+	prb.Add("debug 1", debug)
+	prb.Add("crash", crash)
+	prb.Add("debug 2", debug)
+
+	// The oldest of the lowest priority values is evicted.
+	dropped, ok := prb.Add("debug 3", debug)
+	assertEqual(t, "debug 1", dropped)
+	assertEqual(t, true, ok)
+
+	for range 10 {
+		prb.Add("debug", debug)
+	}
+
+	vals, _ := prb.Take(10)
+	assertEqual(t, []string{"debug", "debug", "crash"}, vals)
+
+	// Values with higher priorities win, and lower priorities are rejected.
+	prb.Add("warn 1", 5)
+	prb.Add("warn 2", 5)
+	dropped, ok = prb.Add("info", 1)
+	assertEqual(t, "info", dropped)
+	assertEqual(t, true, ok)
+
+	var priorities []int
+	prb.Walk(func(_ string, priority int) error {
+		priorities = append(priorities, priority)
+		return nil
+	})
+	assertEqual(t, []int{5, 5, crash}, priorities)
+}
@@ -0,0 +1,68 @@
+package rb
+
+// PublishEvery enables a read-optimized mode, in which a read-only snapshot of
+// the ring buffer is published after every n modifications, and can be read
+// via Published without taking the lock. Each publish copies the entire ring
+// buffer, so larger values of n trade freshness for lower write overhead. Use
+// Flush to publish immediately, e.g. so a writer can read its own writes. If
+// n <= 0, the mode is disabled, and any published snapshot is discarded.
+func (rb *RingBuffer[T]) PublishEvery(n int) {
+	rb.lock()
+	defer rb.unlock()
+
+	rb.publishEvery = max(0, n)
+	rb.setFlag(flagPublish, rb.publishEvery > 0)
+	rb.token.Store(rb.gen) // see Token
+
+	if rb.publishEvery == 0 {
+		rb.published.Store(nil)
+		return
+	}
+
+	rb.publish()
+}
+
+// Flush publishes a snapshot immediately, if there are modifications which
+// haven't been published yet, so that they're visible to readers of Published,
+// regardless of PublishEvery. If the read-optimized mode isn't enabled, it's a
+// no-op.
+func (rb *RingBuffer[T]) Flush() {
+	rb.lock()
+	defer rb.unlock()
+
+	if rb.publishEvery > 0 && rb.unpublished > 0 {
+		rb.publish()
+	}
+}
+
+// Published returns the most recently published snapshot of the values in the
+// ring buffer, newest first. In the read-optimized mode enabled by PublishEvery,
+// it never takes the lock, and so never blocks, or is blocked by, writers. The
+// returned slice is shared, and must not be modified. If the mode isn't
+// enabled, Published returns all values read under the lock, like Take.
+func (rb *RingBuffer[T]) Published() []T {
+	if p := rb.published.Load(); p != nil {
+		return p.vals
+	}
+
+	rb.lock()
+	defer rb.unlock()
+
+	vals := make([]T, rb.len)
+	rb.copy(vals)
+	return vals
+}
+
+// publishedSnapshot is a snapshot of the values in a ring buffer.
+type publishedSnapshot[T any] struct {
+	vals []T
+	gen  uint64 // of the ring buffer when it was published, see Token
+}
+
+// publish assumes the lock is held.
+func (rb *RingBuffer[T]) publish() {
+	vals := make([]T, rb.len)
+	rb.copy(vals)
+	rb.published.Store(&publishedSnapshot[T]{vals: vals, gen: rb.gen})
+	rb.unpublished = 0
+}
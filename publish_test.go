@@ -0,0 +1,59 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestPublished(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](5)
+	rb.Add(1)
+
+	// Without the mode enabled, reads are current.
+	assertEqual(t, []int{1}, rb.Published())
+
+	// Enabling the mode publishes immediately.
+	rb.PublishEvery(3)
+	assertEqual(t, []int{1}, rb.Published())
+
+	// Writes only become visible every n changes.
+	rb.Add(2)
+	rb.Add(3)
+	assertEqual(t, []int{1}, rb.Published())
+	rb.Add(4)
+	assertEqual(t, []int{4, 3, 2, 1}, rb.Published())
+
+	// Flush publishes pending writes immediately, and the count restarts.
+	rb.Add(5)
+	assertEqual(t, []int{4, 3, 2, 1}, rb.Published())
+	rb.Flush()
+	assertEqual(t, []int{5, 4, 3, 2, 1}, rb.Published())
+	rb.Add(6)
+	rb.Add(7)
+	assertEqual(t, []int{5, 4, 3, 2, 1}, rb.Published())
+
+	// Disabling the mode makes reads current again.
+	rb.PublishEvery(0)
+	assertEqual(t, []int{7, 6, 5, 4, 3}, rb.Published())
+	rb.Flush() // no-op
+	rb.Add(8)
+	assertEqual(t, []int{8, 7, 6, 5, 4}, rb.Published())
+}
+
+func BenchmarkPublished(b *testing.B) {
+	rb := rb.NewRingBuffer[int](1000)
+	for i := range 1000 {
+		rb.Add(i)
+	}
+	rb.PublishEvery(100)
+
+	b.ReportAllocs()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			_ = rb.Published()
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package rb
+
+import (
+	"context"
+	"time"
+)
+
+// Pump moves values from a ring buffer to a sink, e.g. a file or a message
+// queue, via a consumer in a ConsumerGroup. It repeatedly fetches a batch of
+// values, passes them to the sink, and acknowledges them only if the sink
+// succeeds. If the sink fails, the same values are retried after a backoff.
+//
+// Delivery is at-least-once: if the pump stops after the sink succeeds but
+// before the offset is committed, the batch is delivered again. Sinks which
+// are idempotent, e.g. by deduplicating on sequence numbers, get exactly-once
+// delivery. Values which are overwritten in the ring buffer before they're
+// fetched are lost, and reported via OnLoss.
+type Pump[T any] struct {
+	// BatchSize is the maximum number of values passed to each call to the
+	// sink. If it's zero, 100 is used.
+	BatchSize int
+
+	// Backoff is the delay after the first consecutive failure, which doubles
+	// with each subsequent failure, up to 1m. If it's zero, 1s is used.
+	Backoff time.Duration
+
+	// OnLoss, if non-nil, is called with the number of values that were
+	// overwritten before they could be fetched.
+	OnLoss func(n uint64)
+
+	// OnError, if non-nil, is called with every error from the sink, or from
+	// the consumer group.
+	OnError func(error)
+
+	cg       *ConsumerGroup[T]
+	consumer string
+}
+
+// NewPump returns a pump for the named consumer in the consumer group.
+// Exported fields should be set before calling Run.
+func NewPump[T any](cg *ConsumerGroup[T], consumer string) *Pump[T] {
+	return &Pump[T]{
+		cg:       cg,
+		consumer: consumer,
+	}
+}
+
+// Run pumps values to the sink, waiting for new values as necessary, until the
+// context is done.
+func (p *Pump[T]) Run(ctx context.Context, sink func([]T) error) error {
+	batchSize := p.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	initial := p.Backoff
+	if initial <= 0 {
+		initial = time.Second
+	}
+
+	var (
+		backoff = initial
+		lost    uint64 // offset up to which losses have been reported
+	)
+	for {
+		gen := p.cg.rb.Generation()
+
+		n, err := func() (int, error) {
+			offset, err := p.cg.offset(p.consumer)
+			if err != nil {
+				return 0, err
+			}
+
+			entries, err := p.cg.Fetch(p.consumer, batchSize)
+			if err != nil || len(entries) == 0 {
+				return 0, err
+			}
+
+			if first := entries[0].Seq; first > max(offset, lost) {
+				if p.OnLoss != nil {
+					p.OnLoss(first - max(offset, lost))
+				}
+				lost = first
+			}
+
+			vals := make([]T, len(entries))
+			for i, e := range entries {
+				vals[i] = e.Value
+			}
+			if err := sink(vals); err != nil {
+				return 0, err
+			}
+
+			return len(entries), p.cg.Ack(p.consumer, entries[len(entries)-1].Seq)
+		}()
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+
+		case err != nil:
+			if p.OnError != nil {
+				p.OnError(err)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, time.Minute)
+
+		case n > 0:
+			backoff = initial // there may be more values, so don't wait
+
+		default:
+			backoff = initial
+			if _, err := p.cg.rb.Wait(ctx, gen); err != nil {
+				return err
+			}
+		}
+	}
+}
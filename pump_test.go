@@ -0,0 +1,66 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestPump(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](4)
+	for i := range 6 {
+		buf.Add(i) // 0 and 1 are overwritten
+	}
+
+	cg := rb.NewConsumerGroup(buf, nil)
+	p := rb.NewPump(cg, "sink")
+	p.BatchSize = 3
+	p.Backoff = time.Millisecond
+
+	lost := make(chan uint64, 1)
+	p.OnLoss = func(n uint64) { lost <- n }
+	var errs int
+	p.OnError = func(error) { errs++ }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		batches [][]int
+		fail    = true
+		done    = make(chan error)
+	)
+	go func() {
+		done <- p.Run(ctx, func(vals []int) error {
+			if fail {
+				fail = false
+				return errors.New("sink unavailable")
+			}
+			batches = append(batches, vals)
+			if len(batches) == 3 {
+				cancel()
+			}
+			return nil
+		})
+	}()
+
+	assertEqual(t, uint64(2), <-lost)
+
+	// Wait for the pump to catch up before adding more values.
+	for {
+		if entries, _ := cg.Fetch("sink", 1); len(entries) == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	buf.Add(6)
+
+	assertEqual(t, true, errors.Is(<-done, context.Canceled))
+	assertEqual(t, [][]int{{2, 3, 4}, {5}, {6}}, batches)
+	assertEqual(t, 1, errs)
+}
@@ -0,0 +1,192 @@
+package rb
+
+import (
+	"container/heap"
+	"slices"
+	"sort"
+)
+
+// CopyIf is like Copy, but only copies values for which pred returns true. The
+// filtering happens in a single pass, under the lock.
+func (rb *RingBuffer[T]) CopyIf(dst []T, pred func(T) bool) (int, error) {
+	rb.lock()
+	defer rb.unlock()
+
+	var n int
+	for i := 0; i < rb.len && n < len(dst); i++ {
+		if val := rb.buf[rb.index(i)]; pred(val) {
+			dst[n] = val
+			n += 1
+		}
+	}
+	return n, nil
+}
+
+// TakeIf is like Take, but only takes values for which pred returns true.
+func (rb *RingBuffer[T]) TakeIf(n int, pred func(T) bool) ([]T, error) {
+	rb.lock()
+	defer rb.unlock()
+
+	dst := make([]T, 0, min(n, rb.len))
+	for i := 0; i < rb.len && len(dst) < n; i++ {
+		if val := rb.buf[rb.index(i)]; pred(val) {
+			dst = append(dst, val)
+		}
+	}
+	return dst, nil
+}
+
+// ToMap returns a map of the values in the ring buffer, keyed by the result of
+// the key function. If multiple values have the same key, the most recent value
+// wins. This is a function rather than a method, because methods can't have
+// their own type parameters.
+func ToMap[T any, K comparable](rb *RingBuffer[T], key func(T) K) map[K]T {
+	rb.lock()
+	defer rb.unlock()
+
+	m := map[K]T{}
+	for i := range rb.len {
+		val := rb.buf[rb.index(i)]
+		k := key(val)
+		if _, ok := m[k]; !ok {
+			m[k] = val
+		}
+	}
+	return m
+}
+
+// GroupBy partitions the values in the ring buffer into a new set of ring
+// buffers, with size sz, by the category returned by the key function. The
+// relative order of values is preserved within each category.
+func (rb *RingBuffer[T]) GroupBy(key func(T) string, sz int) *RingBuffers[T] {
+	rbs := NewRingBuffers[T](sz)
+
+	rb.lock()
+	defer rb.unlock()
+
+	for _, segment := range rb.segments() {
+		for _, val := range segment {
+			rbs.GetOrCreate(key(val)).Add(val)
+		}
+	}
+
+	return rbs
+}
+
+// Map returns a new ring buffer with the same capacity as src, containing the
+// result of fn for each value in src, in the same order. This is a function
+// rather than a method, because methods can't have their own type parameters.
+func Map[A, B any](src *RingBuffer[A], fn func(A) B) *RingBuffer[B] {
+	src.lock()
+	defer src.unlock()
+
+	dst := NewRingBuffer[B](len(src.buf))
+	for _, segment := range src.segments() {
+		for _, val := range segment {
+			dst.add(fn(val))
+		}
+	}
+
+	return dst
+}
+
+// TakeSorted returns up to n values from the ring buffer, ordered by the less
+// function rather than by recency. All values are considered, from a single
+// snapshot. Values which compare as equal remain ordered newest first.
+func (rb *RingBuffer[T]) TakeSorted(n int, less func(a, b T) bool) []T {
+	rb.lock()
+	vals := make([]T, rb.len)
+	rb.copy(vals)
+	rb.unlock()
+
+	slices.SortStableFunc(vals, func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return vals[:min(max(0, n), len(vals))]
+}
+
+// TopK returns the k values in the ring buffer with the highest scores, highest
+// first. It makes a single pass over the values under the lock, maintaining a
+// bounded heap, and so avoids sorting the whole buffer. Among values with equal
+// scores, more recent values are preferred.
+func (rb *RingBuffer[T]) TopK(k int, score func(T) float64) []T {
+	if k <= 0 {
+		return []T{}
+	}
+
+	rb.lock()
+	defer rb.unlock()
+
+	h := &scoreHeap[T]{}
+	for i := range rb.len {
+		val := rb.buf[rb.index(i)]
+		s := score(val)
+		switch {
+		case h.Len() < k:
+			heap.Push(h, scored[T]{val, s})
+		case s > (*h)[0].score:
+			(*h)[0] = scored[T]{val, s}
+			heap.Fix(h, 0)
+		}
+	}
+
+	// Popping from the min-heap yields the lowest scores first.
+	top := make([]T, h.Len())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i] = heap.Pop(h).(scored[T]).val
+	}
+
+	return top
+}
+
+type scored[T any] struct {
+	val   T
+	score float64
+}
+
+// scoreHeap is a min-heap of scored values.
+type scoreHeap[T any] []scored[T]
+
+func (h scoreHeap[T]) Len() int           { return len(h) }
+func (h scoreHeap[T]) Less(i, j int) bool { return h[i].score < h[j].score }
+func (h scoreHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *scoreHeap[T]) Push(x any)        { *h = append(*h, x.(scored[T])) }
+func (h *scoreHeap[T]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// SearchFirst uses binary search to find the oldest value for which pred returns
+// true, in a ring buffer whose values are ordered such that pred returns false
+// for some number of the oldest values, and true for all of the remaining
+// values. For example, if values contain timestamps which increase over time,
+// then pred might report whether a value's timestamp is at or after some point
+// in time. It's O(log n) rather than O(n).
+//
+// If found, it returns the value, and its index counting from the oldest value,
+// which is index 0. Note that this is the opposite direction to Walk and Take.
+func (rb *RingBuffer[T]) SearchFirst(pred func(T) bool) (index int, v T, ok bool) {
+	rb.lock()
+	defer rb.unlock()
+
+	// Index 0 is the oldest value.
+	at := func(i int) T { return rb.buf[rb.index(rb.len-1-i)] }
+
+	index = sort.Search(rb.len, func(i int) bool { return pred(at(i)) })
+	if index >= rb.len {
+		var zero T
+		return rb.len, zero, false
+	}
+
+	return index, at(index), true
+}
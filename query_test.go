@@ -0,0 +1,171 @@
+package rb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestCopyTakeIf(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](8)
+	for i := range 10 {
+		rb.Add(i)
+	}
+
+	even := func(i int) bool { return i%2 == 0 }
+
+	dst := make([]int, 3)
+	n, err := rb.CopyIf(dst, even)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, 3, n)
+	assertEqual(t, []int{8, 6, 4}, dst)
+
+	dst = make([]int, 10)
+	n, _ = rb.CopyIf(dst, even)
+	assertEqual(t, []int{8, 6, 4, 2}, dst[:n])
+
+	vals, err := rb.TakeIf(2, even)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []int{8, 6}, vals)
+
+	vals, _ = rb.TakeIf(100, even)
+	assertEqual(t, []int{8, 6, 4, 2}, vals)
+
+	vals, _ = rb.TakeIf(0, even)
+	assertEqual(t, []int{}, vals)
+}
+
+func TestToMap(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		id    string
+		state string
+	}
+
+	events := rb.NewRingBuffer[event](10)
+	events.Add(event{"a", "created"})
+	events.Add(event{"b", "created"})
+	events.Add(event{"a", "running"})
+	events.Add(event{"c", "created"})
+	events.Add(event{"a", "done"})
+
+	latest := rb.ToMap(events, func(e event) string { return e.id })
+	assertEqual(t, 3, len(latest))
+	assertEqual(t, "done", latest["a"].state)
+	assertEqual(t, "created", latest["b"].state)
+	assertEqual(t, "created", latest["c"].state)
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](6)
+	for i := range 10 {
+		rb.Add(i)
+	}
+
+	parity := func(i int) string {
+		if i%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	}
+
+	rbs := rb.GroupBy(parity, 2)
+	all := rbs.GetAll()
+	assertEqual(t, 2, len(all))
+
+	even, _ := all["even"].Take(10)
+	odd, _ := all["odd"].Take(10)
+	assertEqual(t, []int{8, 6}, even)
+	assertEqual(t, []int{9, 7}, odd)
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	src := rb.NewRingBuffer[int](4)
+	for i := range 6 {
+		src.Add(i)
+	}
+
+	dst := rb.Map(src, func(i int) string { return fmt.Sprintf("<%d>", i) })
+	vals, _ := dst.Take(10)
+	assertEqual(t, []string{"<5>", "<4>", "<3>", "<2>"}, vals)
+	assertEqual(t, 4, dst.Stats().Capacity)
+}
+
+func TestTakeSorted(t *testing.T) {
+	t.Parallel()
+
+	type req struct {
+		ID  int
+		Dur int
+	}
+
+	rb := rb.NewRingBuffer[req](5)
+	for i, dur := range []int{50, 10, 30, 50, 20, 40} {
+		rb.Add(req{i, dur})
+	}
+
+	slowest := func(a, b req) bool { return a.Dur > b.Dur }
+	assertEqual(t, 0, len(rb.TakeSorted(0, slowest)))
+	assertEqual(t, []req{{3, 50}, {5, 40}}, rb.TakeSorted(2, slowest))
+	assertEqual(t, []req{{3, 50}, {5, 40}, {2, 30}, {4, 20}, {1, 10}}, rb.TakeSorted(10, slowest))
+}
+
+func TestTopK(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](10)
+	for _, i := range []int{5, 1, 9, 3, 7, 2, 8} {
+		rb.Add(i)
+	}
+
+	identity := func(i int) float64 { return float64(i) }
+	assertEqual(t, []int{}, rb.TopK(0, identity))
+	assertEqual(t, []int{9}, rb.TopK(1, identity))
+	assertEqual(t, []int{9, 8, 7}, rb.TopK(3, identity))
+	assertEqual(t, []int{9, 8, 7, 5, 3, 2, 1}, rb.TopK(100, identity))
+
+	negative := func(i int) float64 { return -float64(i) }
+	assertEqual(t, []int{1, 2}, rb.TopK(2, negative))
+}
+
+func TestSearchFirst(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](5)
+
+	atLeast := func(n int) func(int) bool { return func(i int) bool { return i >= n } }
+
+	_, _, ok := rb.SearchFirst(atLeast(0))
+	assertEqual(t, false, ok)
+
+	for i := range 8 {
+		rb.Add(i * 10) // 30, 40, 50, 60, 70 remain, wrapped
+	}
+
+	for _, tc := range []struct {
+		n     int
+		index int
+		v     int
+		ok    bool
+	}{
+		{0, 0, 30, true},
+		{30, 0, 30, true},
+		{31, 1, 40, true},
+		{55, 3, 60, true},
+		{70, 4, 70, true},
+		{71, 5, 0, false},
+	} {
+		index, v, ok := rb.SearchFirst(atLeast(tc.n))
+		assertEqual(t, tc.index, index)
+		assertEqual(t, tc.v, v)
+		assertEqual(t, tc.ok, ok)
+	}
+}
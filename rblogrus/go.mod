@@ -0,0 +1,13 @@
+module github.com/peterbourgon/rb/rblogrus
+
+go 1.24
+
+require (
+	github.com/google/go-cmp v0.7.0
+	github.com/peterbourgon/rb v0.0.0-00010101000000-000000000000
+	github.com/sirupsen/logrus v1.10.2
+)
+
+require golang.org/x/sys v0.13.0 // indirect
+
+replace github.com/peterbourgon/rb => ../
@@ -0,0 +1,61 @@
+// Package rblogrus mirrors logrus entries into ring buffers, via an
+// rb.LogMirror. It's a separate module, so package rb doesn't depend on logrus.
+package rblogrus
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/peterbourgon/rb"
+	"github.com/sirupsen/logrus"
+)
+
+// Hook is a logrus.Hook which mirrors entries into a log mirror. Levels are
+// named like those of log/slog, e.g. "INFO" and "WARN", so entries from both
+// share categories in LogByLevel. The logger name of each entry is the value of
+// the field "logger", if any, which is otherwise kept as a field.
+type Hook struct {
+	mirror *rb.LogMirror
+	levels []logrus.Level
+}
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// NewHook returns a hook which mirrors entries at the given levels into the log
+// mirror. If no levels are given, entries at all levels are mirrored.
+func NewHook(m *rb.LogMirror, levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+	return &Hook{mirror: m, levels: levels}
+}
+
+// Levels implements logrus.Hook.
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire implements logrus.Hook.
+func (h *Hook) Fire(e *logrus.Entry) error {
+	entry := rb.LogEntry{
+		Time:    e.Time,
+		Level:   levelName(e.Level),
+		Message: e.Message,
+		Fields:  maps.Clone(map[string]any(e.Data)),
+	}
+	if logger, ok := entry.Fields["logger"].(string); ok {
+		entry.Logger = logger
+		delete(entry.Fields, "logger")
+	}
+
+	h.mirror.Mirror(entry)
+	return nil
+}
+
+// levelName returns the name of the level, like those of log/slog.
+func levelName(level logrus.Level) string {
+	if level == logrus.WarnLevel {
+		return "WARN"
+	}
+	return strings.ToUpper(level.String())
+}
@@ -0,0 +1,43 @@
+package rblogrus_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rblogrus"
+	"github.com/sirupsen/logrus"
+)
+
+func TestHook(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[rb.LogEntry](10)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(rblogrus.NewHook(rb.NewLogMirror(rbs, nil), logrus.InfoLevel, logrus.WarnLevel))
+
+	logger.Debug("ignored")
+	logger.WithFields(logrus.Fields{"logger": "http", "status": 200}).Info("request")
+	logger.Warn("slow")
+	logger.Error("not mirrored")
+
+	info, _ := rbs.GetOrCreate("INFO").Take(10)
+	want := []rb.LogEntry{{
+		Level:   "INFO",
+		Logger:  "http",
+		Message: "request",
+		Fields:  map[string]any{"status": 200},
+	}}
+	if diff := cmp.Diff(want, info, cmpopts.IgnoreFields(rb.LogEntry{}, "Time")); diff != "" {
+		t.Errorf("INFO: (-want +have)\n%s", diff)
+	}
+
+	for level, want := range map[string]int{"WARN": 1, "ERROR": 0, "DEBUG": 0} {
+		if _, _, have := rbs.GetOrCreate(level).Overview(); want != have {
+			t.Errorf("%s: want %d, have %d", level, want, have)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+// Package rbtest provides helpers for testing code which uses package rb.
+package rbtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+// Fake is an rb.Buffer backed by a real ring buffer, whose behavior can be
+// scripted by tests, e.g. to make walks fail, or to drop added values.
+//
+// It's safe for concurrent use by multiple goroutines.
+type Fake[T any] struct {
+	rb *rb.RingBuffer[T]
+
+	mtx      sync.Mutex
+	walkErr  error
+	dropAdds int
+	calls    []string
+}
+
+var _ rb.Buffer[int] = (*Fake[int])(nil)
+
+// NewFake returns an empty fake buffer with the given size.
+func NewFake[T any](sz int) *Fake[T] {
+	return &Fake[T]{
+		rb: rb.NewRingBuffer[T](sz),
+	}
+}
+
+// FailWalk makes subsequent calls to Walk return err, without calling the walk
+// function. Pass nil to make walks succeed again.
+func (f *Fake[T]) FailWalk(err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.walkErr = err
+}
+
+// DropAdds makes the next n calls to Add drop the given value, rather than
+// storing it, and return it as the dropped value.
+func (f *Fake[T]) DropAdds(n int) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.dropAdds = max(0, n)
+}
+
+// Calls returns the names of the methods called on the fake so far, in order.
+func (f *Fake[T]) Calls() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	return append([]string(nil), f.calls...)
+}
+
+// RingBuffer returns the underlying ring buffer, e.g. to enable timestamps.
+// Calls made directly to the ring buffer aren't recorded, or scripted.
+func (f *Fake[T]) RingBuffer() *rb.RingBuffer[T] {
+	return f.rb
+}
+
+// Add implements rb.Buffer.
+func (f *Fake[T]) Add(val T) (dropped T, ok bool) {
+	f.mtx.Lock()
+	f.calls = append(f.calls, "Add")
+	drop := f.dropAdds > 0
+	if drop {
+		f.dropAdds -= 1
+	}
+	f.mtx.Unlock()
+
+	if drop {
+		return val, true
+	}
+
+	return f.rb.Add(val)
+}
+
+// Walk implements rb.Buffer.
+func (f *Fake[T]) Walk(fn func(T) error) error {
+	f.mtx.Lock()
+	f.calls = append(f.calls, "Walk")
+	err := f.walkErr
+	f.mtx.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return f.rb.Walk(fn)
+}
+
+// Take implements rb.Buffer.
+func (f *Fake[T]) Take(n int) ([]T, error) {
+	f.record("Take")
+	return f.rb.Take(n)
+}
+
+// Overview implements rb.Buffer.
+func (f *Fake[T]) Overview() (newest, oldest T, count int) {
+	f.record("Overview")
+	return f.rb.Overview()
+}
+
+// Resize implements rb.Buffer.
+func (f *Fake[T]) Resize(sz int) (dropped []T) {
+	f.record("Resize")
+	return f.rb.Resize(sz)
+}
+
+func (f *Fake[T]) record(method string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.calls = append(f.calls, method)
+}
+
+// Clock is a manually controlled clock, whose Now method can be passed to
+// functions like rb.RingBuffer.EnableTimestamps to freeze time in tests.
+//
+// It's safe for concurrent use by multiple goroutines.
+type Clock struct {
+	mtx sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock which is frozen at the given time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to the given time.
+func (c *Clock) Set(now time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.now = now
+}
+
+// AssertContents fails the test if the values in the buffer, newest first,
+// aren't deeply equal to want.
+func AssertContents[T any](t testing.TB, buf rb.Buffer[T], want []T) {
+	t.Helper()
+
+	_, _, count := buf.Overview()
+	have, err := buf.Take(count)
+	if err != nil {
+		t.Errorf("Take: %v", err)
+		return
+	}
+
+	if len(have) == 0 && len(want) == 0 {
+		return
+	}
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("contents: want %s, have %s", format(want), format(have))
+	}
+}
+
+func format[T any](vals []T) string {
+	return fmt.Sprintf("%d value(s) %v", len(vals), vals)
+}
+
+// ListStore is an in-memory rb.ListStore, with the semantics of Redis lists,
+// for testing code which uses rb.StoreBuffer, and as a reference for drivers.
+//
+// It's safe for concurrent use by multiple goroutines.
+type ListStore struct {
+	mtx   sync.Mutex
+	lists map[string][][]byte
+}
+
+var _ rb.ListStore = (*ListStore)(nil)
+
+// NewListStore returns an empty list store.
+func NewListStore() *ListStore {
+	return &ListStore{lists: map[string][][]byte{}}
+}
+
+// LPush implements rb.ListStore.
+func (s *ListStore) LPush(ctx context.Context, key string, val []byte) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.lists[key] = append([][]byte{append([]byte(nil), val...)}, s.lists[key]...)
+	return len(s.lists[key]), nil
+}
+
+// LRange implements rb.ListStore.
+func (s *ListStore) LRange(ctx context.Context, key string, start, stop int) ([][]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	list := s.lists[key]
+	start, stop, ok := listRange(len(list), start, stop)
+	if !ok {
+		return [][]byte{}, nil
+	}
+	return append([][]byte{}, list[start:stop+1]...), nil
+}
+
+// LTrim implements rb.ListStore.
+func (s *ListStore) LTrim(ctx context.Context, key string, start, stop int) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	list := s.lists[key]
+	start, stop, ok := listRange(len(list), start, stop)
+	if !ok {
+		delete(s.lists, key)
+		return nil
+	}
+	s.lists[key] = list[start : stop+1]
+	return nil
+}
+
+// listRange normalizes Redis-style indexes for a list of length n.
+func listRange(n, start, stop int) (int, int, bool) {
+	if start < 0 {
+		start = max(0, n+start)
+	}
+	if stop < 0 {
+		stop = n + stop
+	}
+	stop = min(stop, n-1)
+	return start, stop, start <= stop
+}
@@ -0,0 +1,114 @@
+package rbtest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestFake(t *testing.T) {
+	t.Parallel()
+
+	f := rbtest.NewFake[int](3)
+	f.Add(1)
+
+	f.DropAdds(1)
+	if dropped, ok := f.Add(2); !ok || dropped != 2 {
+		t.Errorf("Add: want dropped 2, have %v %v", dropped, ok)
+	}
+	f.Add(3)
+	rbtest.AssertContents[int](t, f, []int{3, 1})
+
+	errBoom := errors.New("boom")
+	f.FailWalk(errBoom)
+	if err := f.Walk(func(int) error { t.Error("walk function called"); return nil }); !errors.Is(err, errBoom) {
+		t.Errorf("Walk: want %v, have %v", errBoom, err)
+	}
+
+	want := []string{"Add", "Add", "Add", "Overview", "Take", "Walk"}
+	if have := f.Calls(); !reflect.DeepEqual(want, have) {
+		t.Errorf("Calls: want %v, have %v", want, have)
+	}
+}
+
+func TestClock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := rbtest.NewClock(start)
+
+	f := rbtest.NewFake[string](3)
+	f.RingBuffer().EnableTimestamps(c.Now)
+	f.Add("a")
+	c.Advance(time.Minute)
+	f.Add("b")
+
+	if have := f.RingBuffer().CountSince(start.Add(time.Second)); have != 1 {
+		t.Errorf("CountSince: want 1, have %d", have)
+	}
+}
+
+func TestAssertContents(t *testing.T) {
+	t.Parallel()
+
+	f := rbtest.NewFake[int](3)
+	rbtest.AssertContents[int](t, f, nil)
+
+	f.Add(1)
+	ft := &fakeT{TB: t}
+	rbtest.AssertContents[int](ft, f, []int{2})
+	if !ft.failed {
+		t.Errorf("AssertContents didn't fail")
+	}
+}
+
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (t *fakeT) Helper()               {}
+func (t *fakeT) Errorf(string, ...any) { t.failed = true }
+
+func TestListStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := rbtest.NewListStore()
+	for _, v := range []string{"a", "b", "c", "d"} {
+		s.LPush(ctx, "k", []byte(v))
+	}
+
+	str := func(vals [][]byte) []string {
+		strs := []string{}
+		for _, v := range vals {
+			strs = append(strs, string(v))
+		}
+		return strs
+	}
+
+	for _, tc := range []struct {
+		start, stop int
+		want        []string
+	}{
+		{0, -1, []string{"d", "c", "b", "a"}},
+		{1, 2, []string{"c", "b"}},
+		{-2, -1, []string{"b", "a"}},
+		{3, 10, []string{"a"}},
+		{5, 10, []string{}},
+	} {
+		vals, _ := s.LRange(ctx, "k", tc.start, tc.stop)
+		if have := str(vals); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("LRange(%d, %d): want %v, have %v", tc.start, tc.stop, tc.want, have)
+		}
+	}
+
+	s.LTrim(ctx, "k", 0, 1)
+	if vals, _ := s.LRange(ctx, "k", 0, -1); !reflect.DeepEqual([]string{"d", "c"}, str(vals)) {
+		t.Errorf("LTrim: have %v", str(vals))
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/peterbourgon/rb/rbzap
+
+go 1.24
+
+require (
+	github.com/google/go-cmp v0.7.0
+	github.com/peterbourgon/rb v0.0.0-00010101000000-000000000000
+	go.uber.org/zap v1.28.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
+
+replace github.com/peterbourgon/rb => ../
@@ -0,0 +1,59 @@
+// Package rbzap mirrors zap log entries into ring buffers, via an rb.LogMirror.
+// It's a separate module, so package rb doesn't depend on zap.
+package rbzap
+
+import (
+	"github.com/peterbourgon/rb"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewCore returns a zapcore.Core which mirrors entries at levels enabled by
+// enab into the log mirror, e.g. for use with zapcore.NewTee alongside the
+// core which writes the logs. Levels are named like those of log/slog, e.g.
+// "INFO" and "WARN", so entries from both share categories in LogByLevel.
+// Fields are encoded by a zapcore.MapObjectEncoder.
+func NewCore(m *rb.LogMirror, enab zapcore.LevelEnabler) zapcore.Core {
+	return &core{LevelEnabler: enab, mirror: m}
+}
+
+type core struct {
+	zapcore.LevelEnabler
+	mirror *rb.LogMirror
+	fields []zapcore.Field // from With
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	c2 := *c
+	c2.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &c2
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	c.mirror.Mirror(rb.LogEntry{
+		Time:    ent.Time,
+		Level:   ent.Level.CapitalString(),
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Fields:  enc.Fields,
+	})
+	return nil
+}
+
+func (c *core) Sync() error {
+	return nil
+}
@@ -0,0 +1,43 @@
+package rbzap_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCore(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[rb.LogEntry](10)
+	logger := zap.New(rbzap.NewCore(rb.NewLogMirror(rbs, nil), zapcore.InfoLevel))
+
+	logger.Debug("ignored")
+	logger.Named("http").With(zap.String("method", "GET")).Info("request", zap.Int("status", 200))
+	logger.Warn("slow", zap.Duration("took", 0))
+
+	info, _ := rbs.GetOrCreate("INFO").Take(10)
+	want := []rb.LogEntry{{
+		Level:   "INFO",
+		Logger:  "http",
+		Message: "request",
+		Fields:  map[string]any{"method": "GET", "status": int64(200)},
+	}}
+	if diff := cmp.Diff(want, info, cmpopts.IgnoreFields(rb.LogEntry{}, "Time")); diff != "" {
+		t.Errorf("INFO: (-want +have)\n%s", diff)
+	}
+
+	_, _, count := rbs.GetOrCreate("WARN").Overview()
+	if want, have := 1, count; want != have {
+		t.Errorf("WARN: want %d, have %d", want, have)
+	}
+	_, _, count = rbs.GetOrCreate("DEBUG").Overview()
+	if want, have := 0, count; want != have {
+		t.Errorf("DEBUG: want %d, have %d", want, have)
+	}
+}
@@ -0,0 +1,147 @@
+package rb
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// RecordedOp is a single operation recorded by a Recorder, along with its
+// result.
+type RecordedOp[T any] struct {
+	Method  string // "Add", "Resize", or "Take"
+	Value   T      // value passed to Add
+	N       int    // size passed to Resize, or count passed to Take
+	Dropped []T    // values dropped by Add or Resize
+	Taken   []T    // values returned by Take
+	Err     error  // error returned by Take, if any
+}
+
+// Recorder wraps a Buffer, and records every call to Add, Resize, and Take,
+// with its arguments and results, so that they can be reapplied to another
+// buffer with Replay. That's useful for differential fuzzing of Buffer
+// implementations, and for reproducing bug reports.
+//
+// Calls are serialized, so that the recording reflects the exact order in
+// which they were applied. Walk and Overview are passed through, and aren't
+// recorded.
+type Recorder[T any] struct {
+	next Buffer[T]
+
+	mtx sync.Mutex
+	ops []RecordedOp[T]
+}
+
+var _ Buffer[int] = (*Recorder[int])(nil)
+
+// NewRecorder returns a Buffer which delegates to next, and records calls.
+func NewRecorder[T any](next Buffer[T]) *Recorder[T] {
+	return &Recorder[T]{
+		next: next,
+	}
+}
+
+// Ops returns the operations recorded so far, in order.
+func (r *Recorder[T]) Ops() []RecordedOp[T] {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return append([]RecordedOp[T](nil), r.ops...)
+}
+
+// Add implements Buffer.
+func (r *Recorder[T]) Add(val T) (dropped T, ok bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	dropped, ok = r.next.Add(val)
+	op := RecordedOp[T]{Method: "Add", Value: val}
+	if ok {
+		op.Dropped = []T{dropped}
+	}
+	r.ops = append(r.ops, op)
+	return dropped, ok
+}
+
+// Resize implements Buffer.
+func (r *Recorder[T]) Resize(sz int) (dropped []T) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	dropped = r.next.Resize(sz)
+	r.ops = append(r.ops, RecordedOp[T]{Method: "Resize", N: sz, Dropped: dropped})
+	return dropped
+}
+
+// Take implements Buffer.
+func (r *Recorder[T]) Take(n int) ([]T, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	vals, err := r.next.Take(n)
+	r.ops = append(r.ops, RecordedOp[T]{Method: "Take", N: n, Taken: vals, Err: err})
+	return vals, err
+}
+
+// Walk implements Buffer.
+func (r *Recorder[T]) Walk(fn func(T) error) error {
+	return r.next.Walk(fn)
+}
+
+// Overview implements Buffer.
+func (r *Recorder[T]) Overview() (newest, oldest T, count int) {
+	return r.next.Overview()
+}
+
+// Replay applies the recorded operations to dst, in order. If the result of
+// any operation differs from the recorded result, as determined by
+// reflect.DeepEqual, or by comparing error messages, Replay stops and returns
+// an error describing the difference.
+func Replay[T any](dst Buffer[T], ops []RecordedOp[T]) error {
+	for i, op := range ops {
+		var (
+			dropped, taken []T
+			err            error
+		)
+		switch op.Method {
+		case "Add":
+			if d, ok := dst.Add(op.Value); ok {
+				dropped = []T{d}
+			}
+		case "Resize":
+			dropped = dst.Resize(op.N)
+		case "Take":
+			taken, err = dst.Take(op.N)
+		default:
+			return fmt.Errorf("op %d: unknown method %q", i, op.Method)
+		}
+
+		if !equalValues(op.Dropped, dropped) {
+			return fmt.Errorf("op %d: %s: dropped %v, recorded %v", i, op.Method, dropped, op.Dropped)
+		}
+		if errorString(op.Err) != errorString(err) {
+			return fmt.Errorf("op %d: %s: error %v, recorded %v", i, op.Method, err, op.Err)
+		}
+		if !equalValues(op.Taken, taken) {
+			return fmt.Errorf("op %d: %s: took %v, recorded %v", i, op.Method, taken, op.Taken)
+		}
+	}
+	return nil
+}
+
+// errorString returns the message of err, or "" if it's nil, as errors which
+// are recorded and replayed separately are only comparable by their messages.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// equalValues treats nil and empty slices as equal.
+func equalValues[T any](a, b []T) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
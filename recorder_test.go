@@ -0,0 +1,79 @@
+package rb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRecorderReplay(t *testing.T) {
+	t.Parallel()
+
+	r := rb.NewRecorder[int](rb.NewRingBuffer[int](3))
+	for i := range 5 {
+		r.Add(i)
+	}
+	r.Take(2)
+	r.Resize(2)
+	r.Add(5)
+	r.Walk(func(int) error { return nil }) // not recorded
+
+	ops := r.Ops()
+	assertEqual(t, 8, len(ops))
+	assertEqual(t, rb.RecordedOp[int]{Method: "Add", Value: 3, Dropped: []int{0}}, ops[3])
+	assertEqual(t, rb.RecordedOp[int]{Method: "Take", N: 2, Taken: []int{4, 3}}, ops[5])
+	assertEqual(t, rb.RecordedOp[int]{Method: "Resize", N: 2, Dropped: []int{2}}, ops[6])
+
+	// Replaying onto an identical buffer reproduces every result.
+	if err := rb.Replay[int](rb.NewRingBuffer[int](3), ops); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying onto a different buffer reports the first difference.
+	err := rb.Replay[int](rb.NewRingBuffer[int](4), ops)
+	if err == nil || !strings.HasPrefix(err.Error(), "op 3: Add") {
+		t.Fatalf("want op 3 difference, have %v", err)
+	}
+}
+
+func TestRecorderTakeError(t *testing.T) {
+	t.Parallel()
+
+	errTooMany := errors.New("too many")
+	newBuffer := func() rb.Buffer[int] { return takeLimit{rb.NewRingBuffer[int](3), errTooMany} }
+
+	r := rb.NewRecorder(newBuffer())
+	r.Add(1)
+	_, err := r.Take(5)
+	assertEqual(t, true, errors.Is(err, errTooMany))
+
+	// Failed calls are recorded, with their error.
+	ops := r.Ops()
+	assertEqual(t, 2, len(ops))
+	assertEqual(t, "Take", ops[1].Method)
+	assertEqual(t, true, errors.Is(ops[1].Err, errTooMany))
+
+	// Replay compares errors, too.
+	if err := rb.Replay(newBuffer(), ops); err != nil {
+		t.Fatal(err)
+	}
+	err = rb.Replay[int](rb.NewRingBuffer[int](3), ops)
+	if err == nil || !strings.HasPrefix(err.Error(), "op 1: Take: error <nil>") {
+		t.Fatalf("want op 1 difference, have %v", err)
+	}
+}
+
+// takeLimit is a Buffer whose Take fails for more values than its capacity.
+type takeLimit struct {
+	*rb.RingBuffer[int]
+	err error
+}
+
+func (b takeLimit) Take(n int) ([]int, error) {
+	if n > b.Stats().Capacity {
+		return nil, b.err
+	}
+	return b.RingBuffer.Take(n)
+}
@@ -0,0 +1,160 @@
+package rb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+// debugPath is where NewRingBuffersPublished registers Handler.
+const debugPath = "/debug/rb"
+
+// registry holds the sets of ring buffers published by NewRingBuffersPublished.
+var registry struct {
+	mtx  sync.Mutex
+	sets map[string]publishedSet
+	once sync.Once
+}
+
+// publishedSet is the type-erased view of a published RingBuffers.
+type publishedSet interface {
+	Stats() (total Stats, categories map[string]Stats)
+	RenderHTML(w io.Writer, opts HTMLOptions) error
+}
+
+// NewRingBuffersPublished returns a new set of ring buffers of size sz, like
+// NewRingBuffers, which is registered under the given name, in the manner of
+// expvar.Publish. The first call registers Handler at /debug/rb on
+// http.DefaultServeMux, so applications which serve the default mux, e.g. for
+// net/http/pprof, get a debug console without any other wiring. If a handler is
+// already registered at that path, it's left alone, and DisableDebugHandler
+// prevents the registration altogether. It panics if the name is already
+// registered.
+func NewRingBuffersPublished[T any](name string, sz int) *RingBuffers[T] {
+	rbs := NewRingBuffers[T](sz)
+
+	registry.mtx.Lock()
+	defer registry.mtx.Unlock()
+
+	if _, ok := registry.sets[name]; ok {
+		panic(fmt.Sprintf("rb: reuse of published name %q", name))
+	}
+	if registry.sets == nil {
+		registry.sets = map[string]publishedSet{}
+	}
+	registry.sets[name] = rbs
+
+	registry.once.Do(func() {
+		// Registering a duplicate pattern panics.
+		r := &http.Request{Method: http.MethodGet, URL: &url.URL{Path: debugPath}}
+		if _, pattern := http.DefaultServeMux.Handler(r); pattern != debugPath {
+			http.Handle(debugPath, Handler())
+		}
+	})
+
+	return rbs
+}
+
+// DisableDebugHandler prevents NewRingBuffersPublished from registering Handler
+// on http.DefaultServeMux, e.g. for applications which serve it elsewhere, or
+// not at all. It must be called before the first call to
+// NewRingBuffersPublished, and has no effect afterwards.
+func DisableDebugHandler() {
+	registry.once.Do(func() {})
+}
+
+// LookupPublished returns the set of ring buffers published under the given
+// name, if it exists, and has values of type T.
+func LookupPublished[T any](name string) (*RingBuffers[T], bool) {
+	registry.mtx.Lock()
+	defer registry.mtx.Unlock()
+
+	rbs, ok := registry.sets[name].(*RingBuffers[T])
+	return rbs, ok
+}
+
+// Handler returns an HTTP handler which serves the sets of ring buffers
+// published by NewRingBuffersPublished. By default, it renders an HTML index of
+// the published names. The query parameter name renders the categories in that
+// set, and adding the query parameter category renders the values in that
+// category, as with RingBuffers.RenderHTML. The query parameter format=json
+// returns the stats of every published set, or of the named set, as JSON.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			query    = r.URL.Query()
+			name     = query.Get("name")
+			category = query.Get("category")
+			jsonFmt  = query.Get("format") == "json"
+		)
+
+		registry.mtx.Lock()
+		sets := make(map[string]publishedSet, len(registry.sets))
+		for n, set := range registry.sets {
+			if name == "" || n == name {
+				sets[n] = set
+			}
+		}
+		registry.mtx.Unlock()
+
+		if name != "" && len(sets) == 0 {
+			http.Error(w, "name not found", http.StatusNotFound)
+			return
+		}
+
+		switch {
+		case jsonFmt:
+			type setStats struct {
+				Total      Stats            `json:"total"`
+				Categories map[string]Stats `json:"categories"`
+			}
+			resp := make(map[string]setStats, len(sets))
+			for n, set := range sets {
+				total, categories := set.Stats()
+				resp[n] = setStats{Total: total, Categories: categories}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resp)
+
+		case name != "":
+			opts := HTMLOptions{
+				Title:    name,
+				Category: category,
+				Link: func(category string) string {
+					return "?" + url.Values{"name": {name}, "category": {category}}.Encode()
+				},
+			}
+			// Render into a buffer, so an error can still be reported.
+			var buf bytes.Buffer
+			if err := sets[name].RenderHTML(&buf, opts); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			buf.WriteTo(w)
+
+		default:
+			page := htmlPage{Title: "Published ring buffers", Header: []string{"name", "categories", "len", "added"}}
+			for _, n := range slices.Sorted(maps.Keys(sets)) {
+				total, categories := sets[n].Stats()
+				page.Rows = append(page.Rows, htmlRow{
+					Link:  "?" + url.Values{"name": {n}}.Encode(),
+					Cells: []string{n, fmt.Sprint(len(categories)), fmt.Sprint(total.Count), fmt.Sprint(total.Added)},
+				})
+			}
+			var buf bytes.Buffer
+			if err := htmlTemplate.Execute(&buf, page); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			buf.WriteTo(w)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package rb_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestNewRingBuffersPublished(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffersPublished[string]("test-published", 5)
+	rbs.GetOrCreate("http").Add("GET /")
+	rbs.GetOrCreate("http").Add("POST /")
+	rbs.GetOrCreate("grpc").Add("Ping")
+
+	found, ok := rb.LookupPublished[string]("test-published")
+	assertEqual(t, true, ok)
+	assertEqual(t, true, found == rbs)
+	_, ok = rb.LookupPublished[int]("test-published")
+	assertEqual(t, false, ok)
+
+	assertPanics(t, func() { rb.NewRingBuffersPublished[string]("test-published", 5) })
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		body, _ := io.ReadAll(rec.Body)
+		return rec.Code, string(body)
+	}
+
+	code, body := get("/debug/rb")
+	assertEqual(t, 200, code)
+	assertEqual(t, true, strings.Contains(body, `<a href="?name=test-published">test-published</a>`))
+
+	code, body = get("/debug/rb?name=test-published")
+	assertEqual(t, 200, code)
+	assertEqual(t, true, strings.Contains(body, `<a href="?category=http&amp;name=test-published">http</a>`))
+
+	code, body = get("/debug/rb?name=test-published&category=http")
+	assertEqual(t, 200, code)
+	assertEqual(t, true, strings.Contains(body, "POST /"))
+
+	code, body = get("/debug/rb?name=test-published&format=json")
+	assertEqual(t, 200, code)
+	var stats map[string]struct {
+		Total      rb.Stats
+		Categories map[string]rb.Stats
+	}
+	assertEqual(t, error(nil), json.Unmarshal([]byte(body), &stats))
+	assertEqual(t, 3, stats["test-published"].Total.Count)
+	assertEqual(t, 2, stats["test-published"].Categories["http"].Count)
+
+	code, _ = get("/debug/rb?name=missing")
+	assertEqual(t, 404, code)
+	code, body = get("/debug/rb?name=test-published&category=missing")
+	assertEqual(t, 404, code)
+	assertEqual(t, "category \"missing\" not found\n", body)
+}
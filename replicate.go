@@ -0,0 +1,144 @@
+package rb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Frame types in the replication protocol.
+const (
+	frameHeader byte = 1 // payload is the capacity of the source, as a uvarint
+	frameValue  byte = 2 // payload is an encoded value
+)
+
+// Replicate writes the contents of the ring buffer to w, oldest first, and then
+// continues to write every value subsequently added, until the context is done,
+// or a write fails. Combined with Follow on the other end of w, it maintains a
+// replica of the ring buffer, e.g. in a sidecar process.
+//
+// The protocol is a sequence of frames, each of which is a uvarint length,
+// followed by that many bytes: a frame type, and a payload. The first frame is
+// a header, with the capacity of the ring buffer; each subsequent frame is a
+// value, encoded by the codec. Values are delivered as with Subscribe, so if w
+// is slow enough that values are overwritten before they can be written, then
+// they're skipped. Resizing the ring buffer isn't replicated. It's not called
+// Publish, as that would be confused with PublishEvery and Published.
+func (rb *RingBuffer[T]) Replicate(ctx context.Context, w io.Writer, codec Codec[T]) error {
+	rb.lock()
+	capacity := len(rb.buf)
+	rb.unlock()
+
+	if err := writeFrame(w, frameHeader, binary.AppendUvarint(nil, uint64(capacity))); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	return rb.Subscribe(ctx, func(val T) error {
+		data, err := codec.Encode(val)
+		if err != nil {
+			return fmt.Errorf("encode value: %w", err)
+		}
+		if err := writeFrame(w, frameValue, data); err != nil {
+			return fmt.Errorf("write value: %w", err)
+		}
+		return nil
+	})
+}
+
+// Follow reads a replication stream written by Replicate from r, and mirrors it
+// into the ring buffer. When the header is read, the ring buffer is cleared,
+// and resized to the capacity of the source, but at most maxCapacity, so a
+// corrupt or hostile header can't make it allocate without bound; if
+// maxCapacity <= 0, the ring buffer isn't grown beyond its current capacity.
+// After that, each value is added as it's read, so a smaller mirror keeps the
+// newest values. Follow blocks until the stream ends, in which case it returns
+// nil, or until the context is done, or a read fails. A read which is blocked
+// can't observe the context, so callers should also close r, if possible.
+//
+// Follow mirrors into an existing ring buffer, rather than constructing one,
+// because it blocks for the life of the stream, and the mirror should be
+// readable in the meantime.
+func (rb *RingBuffer[T]) Follow(ctx context.Context, r io.Reader, codec Codec[T], maxCapacity int) error {
+	br := bufio.NewReader(r)
+
+	typ, payload, err := readFrame(br)
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	if typ != frameHeader {
+		return fmt.Errorf("read header: unexpected frame type %d", typ)
+	}
+	capacity, n := binary.Uvarint(payload)
+	if n <= 0 || capacity == 0 {
+		return errors.New("read header: invalid capacity")
+	}
+
+	if maxCapacity <= 0 {
+		rb.lock()
+		maxCapacity = len(rb.buf)
+		rb.unlock()
+	}
+
+	rb.Clear()
+	rb.Resize(int(min(capacity, uint64(maxCapacity))))
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		typ, payload, err := readFrame(br)
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return fmt.Errorf("read value: %w", err)
+		case typ != frameValue:
+			return fmt.Errorf("read value: unexpected frame type %d", typ)
+		}
+
+		val, err := codec.Decode(payload)
+		if err != nil {
+			return fmt.Errorf("decode value: %w", err)
+		}
+
+		rb.Add(val)
+	}
+}
+
+// writeFrame writes a single frame to w, in a single call to Write.
+func writeFrame(w io.Writer, typ byte, payload []byte) error {
+	frame := binary.AppendUvarint(nil, uint64(1+len(payload)))
+	frame = append(frame, typ)
+	frame = append(frame, payload...)
+	_, err := w.Write(frame)
+	return err
+}
+
+// readFrame reads a single frame from r. It returns io.EOF only if the stream
+// ends cleanly, between frames.
+func readFrame(r *bufio.Reader) (typ byte, payload []byte, err error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if n == 0 {
+		return 0, nil, errors.New("empty frame")
+	}
+
+	typ, err = r.ReadByte()
+	if err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+
+	var buf bytes.Buffer // grows as data is read, in case n is corrupt
+	if _, err := io.CopyN(&buf, r, int64(n-1)); err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+
+	return typ, buf.Bytes(), nil
+}
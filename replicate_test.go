@@ -0,0 +1,106 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestReplicateFollow(t *testing.T) {
+	t.Parallel()
+
+	codec := rb.JSONCodec[int]{}
+
+	src := rb.NewRingBuffer[int](3)
+	for i := range 5 {
+		src.Add(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pr, pw := io.Pipe()
+
+	replicated := make(chan error, 1)
+	go func() {
+		replicated <- src.Replicate(ctx, pw, codec)
+		pw.Close()
+	}()
+
+	dst := rb.NewRingBuffer[int](1)
+	followed := make(chan error, 1)
+	go func() { followed <- dst.Follow(context.Background(), pr, codec, 10) }()
+
+	waitFor := func(want []int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			vals, _ := dst.Take(10)
+			if len(vals) == len(want) && vals[0] == want[0] {
+				assertEqual(t, want, vals)
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout: want %v, have %v", want, vals)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Initial sync, including the capacity.
+	waitFor([]int{4, 3, 2})
+	assertEqual(t, 3, dst.Stats().Capacity)
+
+	// Live tail.
+	src.Add(5)
+	src.Add(6)
+	waitFor([]int{6, 5, 4})
+
+	// Stopping the source ends the stream.
+	cancel()
+	assertEqual(t, true, errors.Is(<-replicated, context.Canceled))
+	assertEqual(t, error(nil), <-followed)
+}
+
+func TestFollowMaxCapacity(t *testing.T) {
+	t.Parallel()
+
+	codec := rb.JSONCodec[int]{}
+
+	src := rb.NewRingBuffer[int](1000)
+	for i := range 5 {
+		src.Add(i)
+	}
+
+	// Follow until the initial sync fills the mirror, then stop the source.
+	follow := func(dst *rb.RingBuffer[int], maxCapacity, want int) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pr, pw := io.Pipe()
+		go func() { src.Replicate(ctx, pw, codec); pw.Close() }()
+		followed := make(chan error, 1)
+		go func() { followed <- dst.Follow(context.Background(), pr, codec, maxCapacity) }()
+		for dst.Stats().Added < uint64(want) {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		assertEqual(t, error(nil), <-followed)
+	}
+
+	// The capacity of the source is clamped, and the newest values are kept.
+	dst := rb.NewRingBuffer[int](1)
+	follow(dst, 3, 5)
+	assertEqual(t, 3, dst.Stats().Capacity)
+	vals, _ := dst.Take(10)
+	assertEqual(t, []int{4, 3, 2}, vals)
+
+	// Without a max, the ring buffer isn't grown.
+	dst = rb.NewRingBuffer[int](2)
+	follow(dst, 0, 5)
+	assertEqual(t, 2, dst.Stats().Capacity)
+}
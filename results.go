@@ -0,0 +1,84 @@
+package rb
+
+import (
+	"time"
+)
+
+// ResultWindow records the outcomes of recent operations, as successes or
+// failures, and reports statistics about them, for use as e.g. the statistics
+// backend of a circuit breaker.
+//
+// It's safe for concurrent use by multiple goroutines.
+type ResultWindow struct {
+	failures *RingBuffer[bool] // true means failure
+}
+
+// NewResultWindow returns a result window which remembers the last n outcomes.
+// Outcomes are timestamped with the given clock, or time.Now if it's nil.
+func NewResultWindow(n int, now func() time.Time) *ResultWindow {
+	failures := NewRingBuffer[bool](n)
+	failures.EnableTimestamps(now)
+	return &ResultWindow{failures: failures}
+}
+
+// Success records a successful outcome.
+func (w *ResultWindow) Success() {
+	w.failures.Add(false)
+}
+
+// Failure records a failed outcome.
+func (w *ResultWindow) Failure() {
+	w.failures.Add(true)
+}
+
+// Record records a successful outcome if err is nil, and a failure otherwise.
+func (w *ResultWindow) Record(err error) {
+	w.failures.Add(err != nil)
+}
+
+// FailureRatio returns the fraction of recorded outcomes that were failures,
+// and the total number of outcomes. If there are no outcomes, the ratio is 0.
+func (w *ResultWindow) FailureRatio() (ratio float64, total int) {
+	w.failures.lock()
+	defer w.failures.unlock()
+
+	return w.failureRatio(w.failures.len)
+}
+
+// FailureRatioSince is like FailureRatio, but only considers outcomes recorded
+// within the last d.
+func (w *ResultWindow) FailureRatioSince(d time.Duration) (ratio float64, total int) {
+	w.failures.lock()
+	defer w.failures.unlock()
+
+	cutoff := w.failures.clock().Add(-d).UnixNano()
+	return w.failureRatio(w.failures.countSinceTime(cutoff))
+}
+
+// ConsecutiveFailures returns the number of failures recorded since the most
+// recent success.
+func (w *ResultWindow) ConsecutiveFailures() int {
+	w.failures.lock()
+	defer w.failures.unlock()
+
+	var n int
+	for n < w.failures.len && w.failures.buf[w.failures.index(n)] {
+		n += 1
+	}
+	return n
+}
+
+// failureRatio considers the n newest outcomes. It assumes the lock is held.
+func (w *ResultWindow) failureRatio(n int) (float64, int) {
+	if n == 0 {
+		return 0, 0
+	}
+
+	var failures int
+	for i := range n {
+		if w.failures.buf[w.failures.index(i)] {
+			failures += 1
+		}
+	}
+	return float64(failures) / float64(n), n
+}
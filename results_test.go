@@ -0,0 +1,53 @@
+package rb_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestResultWindow(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	w := rb.NewResultWindow(10, clock.Now)
+
+	ratio, total := w.FailureRatio()
+	assertEqual(t, 0.0, ratio)
+	assertEqual(t, 0, total)
+
+	for range 6 {
+		w.Success()
+		clock.Advance(time.Second)
+	}
+	w.Failure()
+	clock.Advance(time.Second)
+	w.Record(errors.New("boom"))
+	clock.Advance(time.Second)
+	w.Record(nil)
+	clock.Advance(time.Second)
+	w.Failure()
+	w.Failure()
+
+	ratio, total = w.FailureRatio()
+	assertEqual(t, 0.4, ratio)
+	assertEqual(t, 10, total)
+	assertEqual(t, 2, w.ConsecutiveFailures())
+
+	// The last 2 seconds contain F, S, F, F.
+	ratio, total = w.FailureRatioSince(2 * time.Second)
+	assertEqual(t, 0.75, ratio)
+	assertEqual(t, 4, total)
+
+	// Old outcomes fall out of the window.
+	for range 10 {
+		w.Failure()
+	}
+	ratio, total = w.FailureRatio()
+	assertEqual(t, 1.0, ratio)
+	assertEqual(t, 10, total)
+	assertEqual(t, 10, w.ConsecutiveFailures())
+}
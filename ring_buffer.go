@@ -1,9 +1,14 @@
 package rb
 
 import (
-	"io"
+	"context"
 	"iter"
+	"runtime/debug"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
 )
 
 // RingBuffer is a fixed-size collection of recent values.
@@ -14,28 +19,135 @@ type RingBuffer[T any] struct {
 	buf []T        // fully allocated at construction
 	cur int        // index for next write, walk backwards to read
 	len int        // count of actual values
+	seq uint64     // sequence number of the next value to be added
+
+	mask    int    // len(buf)-1 if len(buf) is a power of two, otherwise 0
+	gen     uint64 // incremented on every mutation, see Generation
+	evicted uint64 // values evicted to make room for adds, see CapacityController
+
+	meta []meta           // per-value metadata, if tracked, see meta.go
+	now  func() time.Time // clock for timestamps, if enabled
+
+	waitc chan struct{} // closed on mutation, if non-nil, see Wait
+
+	publishEvery int // publish after this many changes, 0 means never
+	unpublished  int // changes since the last publish
+
+	policy EvictionPolicy[T] // nil means evict the oldest value
+
+	cost      func(T) int // cost of each value, if non-nil, see SetCostLimit
+	costLimit int         // max total cost of all values
+	costTotal int         // current total cost of all values
+
+	pin         func(T) bool // values to pin when they're added, see Pin
+	pinFraction float64      // max pinned values, as a fraction of capacity
+	pinned      int          // number of pinned values
+
+	nextExpiry int64         // UnixNano of the earliest TTL expiry, or 0 for none
+	ttl        time.Duration // default TTL for every value, see SetTTL
+
+	pool sync.Pool // of *pooledSlice[T], see TakePooled
+
+	sqlCodec Codec[T] // for Value and Scan, see SetSQLCodec
+
+	labels []label[T] // named snapshots, oldest first, see Label
+
+	order          func(a, b T) bool // if non-nil, see EnforceOrder
+	orderTolerance int               // max distance a value can be reordered
+
+	info Info // immutable after construction, see NewRingBufferWithInfo
+
+	watermarks *watermarks // see OnHighWater and OnLowWater
+
+	deadLetters *deadLetters[T] // rejected values, if recorded, see SetDeadLetters
+
+	lockWait  time.Duration // total time spent waiting for a contended lock
+	contended int           // number of contended lock acquisitions
+
+	// The fields below are read without the lock, by every Add, or by readers
+	// of Published. They're kept on a separate cache line from the fields
+	// above, so those reads don't contend with writes made under the lock.
+	_          cacheLinePad
+	flags      atomic.Uint32                        // optional features, see Add
+	published  atomic.Pointer[publishedSnapshot[T]] // see Published
+	token      atomic.Uint64                        // see Token
+	instrument atomic.Pointer[func(Operation)]      // see Instrument
+	contention atomic.Bool                          // see TrackContention
+	sampleRate atomic.Uint64                        // float64 bits, see SetSampleRate
+	holds      atomic.Uint64                        // odd while held by a callback, see checkHeld
+	heldFor    atomic.Uint32                        // why it's held, see hold
+}
+
+// Flags for optional features, which Add handles in addSlow. Without any of
+// them, Add uses addFast.
+const (
+	flagSample     uint32 = 1 << iota // see SetSampleRate
+	flagInstrument                    // see Instrument
+	flagHooks                         // user functions are called by add, see setHooks
+	flagMeta                          // per-value metadata is tracked, see trackMeta
+	flagWaiters                       // see Wait
+	flagPublish                       // see PublishEvery
+	flagTokens                        // see Token
+	flagWatermarks                    // see OnHighWater and OnLowWater
+)
+
+// setFlag sets or clears the given flag.
+func (rb *RingBuffer[T]) setFlag(flag uint32, set bool) {
+	if set {
+		rb.flags.Or(flag)
+	} else {
+		rb.flags.And(^flag)
+	}
+}
+
+// setHooks updates flagHooks after a user function used by add is set or
+// removed. It assumes the lock is held.
+func (rb *RingBuffer[T]) setHooks() {
+	rb.setFlag(flagHooks, rb.policy != nil || rb.cost != nil || rb.pin != nil || rb.now != nil || rb.order != nil)
 }
 
 // NewRingBuffer returns an empty ring buffer of values of type T, with a
 // pre-allocated and fixed size as defined by sz.
 func NewRingBuffer[T any](sz int) *RingBuffer[T] {
 	return &RingBuffer[T]{
-		buf: make([]T, sz),
+		buf:  make([]T, sz),
+		mask: maskFor(sz),
 	}
 }
 
 // Resize the ring buffer to the given size. If the new size is smaller than the
 // existing size, resize will drop the oldest values as necessary, and return
 // those dropped values. If sz <= 0 it's ignored and the method is a no-op.
+//
+// If the new size fits within the capacity of the existing backing array, e.g.
+// when shrinking, then values are rearranged in place, and nothing is allocated
+// except the returned slice of dropped values. The backing array is retained,
+// so a shrunk ring buffer continues to hold its original memory, as reported
+// by SizeBytes, but can be grown back to its original size without allocating.
 func (rb *RingBuffer[T]) Resize(sz int) (dropped []T) {
 	// Safety first.
 	if sz <= 0 {
 		return nil
 	}
 
-	rb.mtx.Lock()
-	defer rb.mtx.Unlock()
+	op := rb.startOp("Resize")
+	defer op.done()
+
+	rb.lock()
+	defer rb.unlock()
+	op.acquired()
+
+	if sz <= cap(rb.buf) {
+		return rb.resizeInPlace(sz)
+	}
 
+	return rb.reallocate(sz)
+}
+
+// reallocate resizes the ring buffer into a new backing array of exactly the
+// given size, even if it's smaller than the existing one, so the memory held
+// by the existing one can be freed. It assumes the lock is held, and sz > 0.
+func (rb *RingBuffer[T]) reallocate(sz int) (dropped []T) {
 	// Calculate how many values to fill from the old buffer to the new one.
 	fill := min(rb.len, sz)
 
@@ -51,9 +163,18 @@ func (rb *RingBuffer[T]) Resize(sz int) (dropped []T) {
 	buf := make([]T, sz)
 	wrcur := fill - 1
 
+	// Metadata, if tracked, is copied along with values.
+	var md []meta
+	if rb.meta != nil {
+		md = make([]meta, sz)
+	}
+
 	// Copy recent values from the old buffer to the new buffer.
 	for wrcur >= 0 {
 		buf[wrcur] = rb.buf[rdcur]
+		if md != nil {
+			md[wrcur] = rb.meta[rdcur]
+		}
 
 		rdcur -= 1
 		if rdcur < 0 {
@@ -83,33 +204,333 @@ func (rb *RingBuffer[T]) Resize(sz int) (dropped []T) {
 
 	// Modify all of the buffer fields to their new values.
 	rb.buf = buf
+	rb.mask = maskFor(sz)
+	rb.meta = md
 	rb.cur = cur
 	rb.len = fill
+	rb.recost()
+	rb.trimPins()
+	rb.changed()
 
 	// Done.
 	return dropped
 }
 
+// resizeInPlace resizes the ring buffer within the capacity of the existing
+// backing array. It assumes the lock is held, and 0 < sz <= cap(rb.buf).
+func (rb *RingBuffer[T]) resizeInPlace(sz int) (dropped []T) {
+	fill := min(rb.len, sz)
+
+	// Metadata, if tracked, has to fit as well.
+	if rb.meta != nil && sz > cap(rb.meta) {
+		md := make([]meta, len(rb.buf), cap(rb.buf))
+		copy(md, rb.meta)
+		rb.meta = md
+	}
+
+	// Rotate the backing array so the oldest value is at index 0, and the
+	// values are ordered from oldest to newest.
+	if rb.len > 0 {
+		oldest := rb.oldest()
+		rotate(rb.buf, oldest)
+		if rb.meta != nil {
+			rotate(rb.meta, oldest)
+		}
+	}
+
+	// Capture dropped values, newest first, and move the kept values down.
+	drop := rb.len - fill
+	for i := drop - 1; i >= 0; i-- {
+		dropped = append(dropped, rb.buf[i])
+	}
+	copy(rb.buf, rb.buf[drop:rb.len])
+	clear(rb.buf[fill:cap(rb.buf)])
+	if rb.meta != nil {
+		copy(rb.meta, rb.meta[drop:rb.len])
+		clear(rb.meta[fill:cap(rb.meta)])
+		rb.meta = rb.meta[:sz]
+	}
+
+	rb.buf = rb.buf[:sz]
+	rb.mask = maskFor(sz)
+	rb.cur = fill
+	if rb.cur >= sz {
+		rb.cur -= sz
+	}
+	rb.len = fill
+	rb.recost()
+	rb.trimPins()
+	rb.changed()
+
+	return dropped
+}
+
+// rotate s left by k positions, in place.
+func rotate[S ~[]E, E any](s S, k int) {
+	slices.Reverse(s[:k])
+	slices.Reverse(s[k:])
+	slices.Reverse(s)
+}
+
 // Add the value to the ring buffer. If the ring buffer was full, and the oldest
 // value was overwritten by this add, return that oldest/dropped value and true;
-// otherwise, return a zero value and false.
+// otherwise, return a zero value and false. If a cost limit is set, and the add
+// drops more than one value, only the most recently added of them is returned.
 func (rb *RingBuffer[T]) Add(val T) (dropped T, ok bool) {
-	rb.mtx.Lock()
-	defer rb.mtx.Unlock()
+	// Add is the hot path, so optional features are checked with a single load
+	// of the flags, and handled by addSlow. Without them, nothing can panic
+	// while the lock is held, so it's safe to unlock without defer, which is
+	// measurably faster.
+	if rb.flags.Load() != 0 {
+		return rb.addSlow(val)
+	}
+
+	if !rb.mtx.TryLock() {
+		rb.checkHeld()
+		rb.lockSlow()
+	}
 
+	// A feature may have been enabled while the lock was acquired.
+	if rb.flags.Load() != 0 {
+		rb.mtx.Unlock()
+		return rb.addSlow(val)
+	}
+
+	dropped, ok = rb.addFast(val)
+	rb.mtx.Unlock()
+	return dropped, ok
+}
+
+// addFast is add, for a ring buffer without any optional features, see flags.
+// It assumes the lock is held.
+func (rb *RingBuffer[T]) addFast(val T) (dropped T, ok bool) {
+	// Safety first.
+	if cap(rb.buf) <= 0 {
+		return dropped, false
+	}
+
+	if rb.len < len(rb.buf) {
+		rb.len += 1
+	} else {
+		dropped, ok = rb.buf[rb.cur], true
+		rb.evicted += 1
+	}
+
+	rb.buf[rb.cur] = val
+	rb.seq += 1
+	rb.advance()
+
+	rb.gen += 1
+	rb.debugValidate()
+
+	return dropped, ok
+}
+
+// addSlow is Add, with optional features, see flags.
+func (rb *RingBuffer[T]) addSlow(val T) (dropped T, ok bool) {
+	flags := rb.flags.Load()
+
+	if flags&flagSample != 0 && !rb.sample() {
+		return dropped, false
+	}
+
+	if flags&flagInstrument != 0 {
+		return rb.addInstrumented(val)
+	}
+
+	if !rb.mtx.TryLock() {
+		rb.checkHeld()
+		rb.lockSlow()
+	}
+	defer rb.unlock()
+
+	if rb.nextExpiry != 0 {
+		rb.expire(rb.clock())
+	}
+
+	return rb.add(val)
+}
+
+// addInstrumented is Add, with instrumentation, see Instrument.
+func (rb *RingBuffer[T]) addInstrumented(val T) (dropped T, ok bool) {
+	op := rb.startOp("Add")
+	defer op.done()
+
+	if !rb.mtx.TryLock() {
+		rb.checkHeld()
+		rb.lockSlow()
+	}
+	defer rb.unlock()
+	op.acquired()
+
+	if rb.nextExpiry != 0 {
+		rb.expire(rb.clock())
+	}
+
+	return rb.add(val)
+}
+
+// lock the ring buffer, and expire any values past their TTL.
+func (rb *RingBuffer[T]) lock() {
+	if !rb.mtx.TryLock() {
+		rb.checkHeld()
+		rb.lockSlow()
+	}
+
+	if rb.nextExpiry != 0 {
+		rb.expire(rb.clock())
+	}
+}
+
+// unlock the ring buffer, and call any watermark callbacks which fired while it
+// was locked, see OnHighWater.
+func (rb *RingBuffer[T]) unlock() {
+	for _, fn := range rb.release() {
+		fn()
+	}
+}
+
+// release unlocks the ring buffer, and returns any watermark callbacks which
+// fired while it was locked, for the caller to call.
+func (rb *RingBuffer[T]) release() (fired []func()) {
+	if rb.watermarks != nil {
+		fired, rb.watermarks.fired = rb.watermarks.fired, nil
+	}
+	rb.mtx.Unlock()
+	return fired
+}
+
+// tryLock is like lock, but returns false rather than blocking if the lock is
+// held.
+func (rb *RingBuffer[T]) tryLock() bool {
+	if !rb.mtx.TryLock() {
+		return false
+	}
+
+	if rb.nextExpiry != 0 {
+		rb.expire(rb.clock())
+	}
+	return true
+}
+
+// AddTx is like Add, but must be called from within the Do function of the
+// given transaction, which must include this ring buffer. It panics otherwise.
+// Like Add, it's subject to the sample rate, and is reported to Instrument.
+func (rb *RingBuffer[T]) AddTx(tx *Transaction, val T) (dropped T, ok bool) {
+	if !tx.holds(&rb.mtx) {
+		panic("rb: AddTx called outside of a transaction holding the ring buffer")
+	}
+
+	if rb.flags.Load()&flagSample != 0 && !rb.sample() {
+		return dropped, false
+	}
+
+	// The lock is held by the transaction, so there's no wait to report.
+	op := rb.startOp("Add")
+	defer op.done()
+	op.acquired()
+
+	return rb.add(val)
+}
+
+// AddIf adds the value only if cond returns true, given the newest value and
+// the number of values in the ring buffer, and reports whether it was added.
+// Like Add, an added value may cause another value to be dropped.
+// If the ring buffer is empty, newest is the zero value. The check and the add
+// happen atomically under the lock, so e.g. a value can be added only if it
+// differs from the newest value, without a race between Overview and Add.
+func (rb *RingBuffer[T]) AddIf(val T, cond func(newest T, count int) bool) bool {
+	rb.lock()
+	defer rb.unlock()
+
+	var newest T
+	if rb.len > 0 {
+		newest = rb.buf[rb.index(0)]
+	}
+	if !cond(newest, rb.len) {
+		return false
+	}
+
+	// The add may have been rejected, e.g. by an eviction policy.
+	seq := rb.seq
+	rb.add(val)
+	return rb.seq != seq
+}
+
+func (rb *RingBuffer[T]) txMutex() *sync.Mutex {
+	return &rb.mtx
+}
+
+// add assumes the lock is held.
+func (rb *RingBuffer[T]) add(val T) (dropped T, ok bool) {
+	seq := rb.seq
+	dropped, ok = rb.put(val)
+
+	// Notify any waiters, unless the value was rejected.
+	if rb.seq != seq {
+		rb.changed()
+	}
+
+	return dropped, ok
+}
+
+// put is add, without calling changed, so that a batch of values can be added
+// with a single call to changed afterwards. It assumes the lock is held.
+func (rb *RingBuffer[T]) put(val T) (dropped T, ok bool) {
 	// Safety first.
 	if cap(rb.buf) <= 0 {
 		var zero T
 		return zero, false
 	}
 
-	// Capture any overwritten value so it can be returned.
+	// If order is enforced, values which are too far out of order are rejected.
+	if rb.order != nil && rb.outOfOrder(val) {
+		return rb.reject(val)
+	}
+
+	// If the buffer is full, a value has to be evicted. By default, that's the
+	// oldest value which isn't pinned, and if that's the oldest value overall,
+	// it's overwritten below. An eviction policy can choose a different value,
+	// which is removed to make room, or reject the new value.
 	if rb.len >= len(rb.buf) {
-		dropped, ok = rb.buf[rb.cur], true
+		var victim int
+		if rb.policy != nil {
+			victim = rb.policy.Evict(val, View[T]{rb})
+		} else {
+			victim = rb.evictable(0)
+		}
+
+		switch {
+		case victim < 0 || victim >= rb.len:
+			return rb.reject(val)
+		case victim == rb.len-1:
+			dropped, ok = rb.buf[rb.cur], true
+			rb.unpin(rb.cur)
+		default:
+			dropped, ok = rb.remove(victim), true
+		}
+
+		if rb.cost != nil {
+			rb.costTotal -= rb.cost(dropped)
+		}
+		rb.evicted += 1
 	}
 
 	// Write the value at the write cursor.
 	rb.buf[rb.cur] = val
+	if rb.meta != nil {
+		rb.meta[rb.cur] = meta{seq: rb.seq}
+		if rb.now != nil {
+			rb.meta[rb.cur].time = rb.now().UnixNano()
+		}
+		if rb.ttl > 0 {
+			rb.setExpiry(rb.cur, rb.clock().Add(rb.ttl).UnixNano())
+		}
+		if rb.pin != nil && rb.pin(val) {
+			rb.tryPin(rb.cur)
+		}
+	}
+	rb.seq += 1
 
 	// Update the ring buffer size.
 	if rb.len < len(rb.buf) {
@@ -117,19 +538,48 @@ func (rb *RingBuffer[T]) Add(val T) (dropped T, ok bool) {
 	}
 
 	// Advance the write cursor.
-	rb.cur += 1
-	if rb.cur >= len(rb.buf) {
-		rb.cur -= len(rb.buf)
+	rb.advance()
+
+	// Move the value into place, if it's out of order.
+	if rb.order != nil {
+		rb.reorder()
+	}
+
+	// If there's a cost limit, evict as many old values as necessary to get
+	// within that limit, skipping pinned values, though always keeping the
+	// value just added.
+	if rb.cost != nil {
+		rb.costTotal += rb.cost(val)
+		for rb.costTotal > rb.costLimit && rb.len > 1 {
+			dropped, ok = rb.dropForCost(), true
+			rb.evicted += 1
+		}
 	}
 
 	// Done.
 	return dropped, ok
 }
 
+// advance the write cursor. It assumes the lock is held.
+func (rb *RingBuffer[T]) advance() {
+	if rb.mask != 0 {
+		rb.cur = (rb.cur + 1) & rb.mask
+	} else if rb.cur += 1; rb.cur >= len(rb.buf) {
+		rb.cur -= len(rb.buf)
+	}
+}
+
 // Walk calls the given function for each value in the ring buffer, starting
 // with the most recent value, and ending with the oldest value. Walk takes an
-// exclusive lock on the ring buffer, which blocks other calls, including Add.
-func (rb *RingBuffer[T]) Walk(fn func(T) error) error {
+// exclusive lock on the ring buffer, which blocks other calls. The function
+// must not call methods on the same ring buffer, which would deadlock. Such a
+// call panics instead, unless other goroutines are in walks or transactions,
+// on any ring buffer, at the same time, in which case it can't be detected.
+//
+// If the function panics, the panic is recovered and returned as a *PanicError.
+func (rb *RingBuffer[T]) Walk(fn func(T) error) (err error) {
+	defer recoverPanic(&err)
+
 	for val := range rb.All() {
 		if err := fn(val); err != nil {
 			return err
@@ -138,35 +588,124 @@ func (rb *RingBuffer[T]) Walk(fn func(T) error) error {
 	return nil
 }
 
-// All returns an iterator over the values in the ring buffer, starting with the
-// most recent value, and ending with the oldest value. It takes an exclusive
-// lock on the ring buffer for the duration of the iteration, which blocks other
-// calls, including Add. The iterator can be stopped early by breaking from the
-// range loop.
-func (rb *RingBuffer[T]) All() iter.Seq[T] {
-	return func(yield func(T) bool) {
-		rb.mtx.Lock()
-		defer rb.mtx.Unlock()
+// WalkContext is like Walk, but checks the context before each value, and
+// returns the context error if it's done. That bounds the time the lock is held
+// by a slow function to roughly the caller's deadline.
+func (rb *RingBuffer[T]) WalkContext(ctx context.Context, fn func(T) error) (err error) {
+	defer recoverPanic(&err)
 
-		for i := range rb.len {
-			cur := rb.cur - 1 - i
+	for val := range rb.All() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			if cur < 0 {
-				cur += len(rb.buf)
-			}
+// WalkWith is like WalkContext, but passes each value through enrich before
+// calling fn, e.g. to resolve IDs in the value to names, so consumers only see
+// the enriched values. The values are copied under the lock, which is released
+// before enrich and fn are called, so they may be slow, or call methods on the
+// ring buffer. If enrich returns an error, the walk stops and returns it.
+func (rb *RingBuffer[T]) WalkWith(ctx context.Context, enrich func(context.Context, T) (T, error), fn func(T) error) (err error) {
+	defer recoverPanic(&err)
+
+	for _, val := range rb.values() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		val, err := enrich(ctx, val)
+		if err != nil {
+			return err
+		}
+		if err := fn(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			if !yield(rb.buf[cur]) {
+// WalkChunks calls the given function with contiguous chunks of the values in
+// the ring buffer, which amortizes the cost of the function call over many
+// values. Unlike Walk, values are provided oldest first: chunks are ordered
+// from oldest to newest, and so are the values within each chunk. There are at
+// most two chunks, and they're never empty.
+//
+// Chunks refer directly to the ring buffer's internal memory, so they must not
+// be modified, or retained after the function returns. Otherwise, WalkChunks
+// has the same semantics as Walk.
+func (rb *RingBuffer[T]) WalkChunks(fn func([]T) error) (err error) {
+	defer recoverPanic(&err)
+
+	op := rb.startOp("Walk")
+	defer op.done()
+
+	rb.walk(func() {
+		op.acquired()
+		for _, chunk := range rb.segments() {
+			if len(chunk) == 0 {
+				continue
+			}
+			if err = fn(chunk); err != nil {
 				return
 			}
 		}
+	})
+	return err
+}
+
+// segments returns the values in the ring buffer as two contiguous slices of
+// the backing array, which are ordered from oldest to newest, and either or
+// both of which may be empty. It assumes the lock is held.
+func (rb *RingBuffer[T]) segments() [2][]T {
+	oldest := rb.cur - rb.len
+	if oldest < 0 {
+		oldest += len(rb.buf)
+	}
+
+	if oldest+rb.len <= len(rb.buf) {
+		return [2][]T{rb.buf[oldest : oldest+rb.len], nil}
+	}
+
+	return [2][]T{rb.buf[oldest:], rb.buf[:rb.cur]}
+}
+
+// recoverPanic should be deferred, and converts a panic to a *PanicError.
+func recoverPanic(err *error) {
+	if v := recover(); v != nil {
+		*err = &PanicError{Value: v, Stack: debug.Stack()}
+	}
+}
+
+// All returns an iterator over the values in the ring buffer, starting with the
+// most recent value, and ending with the oldest value. It takes an exclusive
+// lock on the ring buffer for the duration of the iteration, with the same
+// semantics as Walk. The iterator can be stopped early by breaking from the
+// range loop.
+func (rb *RingBuffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		op := rb.startOp("Walk")
+		defer op.done()
+
+		rb.walk(func() {
+			op.acquired()
+			for i := range rb.len {
+				if !yield(rb.buf[rb.index(i)]) {
+					return
+				}
+			}
+		})
 	}
 }
 
 // Overview returns the newest and oldest values in the ring buffer, as well as
 // the total number of values stored in the ring buffer.
 func (rb *RingBuffer[T]) Overview() (newest, oldest T, count int) {
-	rb.mtx.Lock()
-	defer rb.mtx.Unlock()
+	rb.lock()
+	defer rb.unlock()
 
 	// The cursor math assumes a non-empty buffer.
 	if rb.len == 0 {
@@ -190,34 +729,63 @@ func (rb *RingBuffer[T]) Overview() (newest, oldest T, count int) {
 	return rb.buf[headidx], rb.buf[tailidx], rb.len
 }
 
+// OverviewN is like Overview, but returns up to k of the newest and k of the
+// oldest values in the ring buffer. Both slices are ordered newest first, so
+// the final element of oldest is the oldest value. If the ring buffer contains
+// fewer than 2k values, some values will appear in both slices.
+func (rb *RingBuffer[T]) OverviewN(k int) (newest, oldest []T, count int) {
+	rb.lock()
+	defer rb.unlock()
+
+	k = min(max(0, k), rb.len)
+	newest = make([]T, k)
+	oldest = make([]T, k)
+
+	for i := range k {
+		newest[i] = rb.buf[rb.index(i)]
+		oldest[i] = rb.buf[rb.index(rb.len-k+i)]
+	}
+
+	return newest, oldest, rb.len
+}
+
 // Copy the most recent values from the ring buffer into dst, newest first.
 // Returns the number of values copied into dst.
 func (rb *RingBuffer[T]) Copy(dst []T) (int, error) {
-	var index int
-	rb.Walk(func(val T) error {
-		if index >= len(dst) {
-			return io.EOF
-		}
-		dst[index] = val
-		index += 1
-		return nil
-	})
-	return index, nil
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.copy(dst), nil
+}
+
+// copy assumes the lock is held.
+func (rb *RingBuffer[T]) copy(dst []T) int {
+	n := min(len(dst), rb.len)
+	for i := range n {
+		dst[i] = rb.buf[rb.index(i)]
+	}
+	return n
+}
+
+// values returns a copy of all of the values in the ring buffer, newest first.
+func (rb *RingBuffer[T]) values() []T {
+	rb.lock()
+	defer rb.unlock()
+
+	vals := make([]T, rb.len)
+	rb.copy(vals)
+	return vals
 }
 
 // Clear drops all elements from the ring buffer, returning them newest first.
 // The capacity of the buffer is unchanged.
 func (rb *RingBuffer[T]) Clear() []T {
-	rb.mtx.Lock()
-	defer rb.mtx.Unlock()
+	rb.lock()
+	defer rb.unlock()
 
 	dropped := make([]T, rb.len)
 	for i := range rb.len {
-		cur := rb.cur - 1 - i
-		if cur < 0 {
-			cur += len(rb.buf)
-		}
-		dropped[i] = rb.buf[cur]
+		dropped[i] = rb.buf[rb.index(i)]
 	}
 
 	var zero T
@@ -227,10 +795,41 @@ func (rb *RingBuffer[T]) Clear() []T {
 
 	rb.cur = 0
 	rb.len = 0
+	rb.costTotal = 0
+	rb.pinned = 0
+	rb.changed()
 
 	return dropped
 }
 
+// oldest returns the index of the oldest value. It assumes the lock is held, and
+// the ring buffer isn't empty.
+func (rb *RingBuffer[T]) oldest() int {
+	cur := rb.cur - rb.len
+	if cur < 0 {
+		cur += len(rb.buf)
+	}
+	return cur
+}
+
+// dropOldest removes the oldest value from the ring buffer, and returns it.
+// Callers should call changed once they're done dropping values. It
+// assumes the lock is held, and the ring buffer isn't empty.
+func (rb *RingBuffer[T]) dropOldest() T {
+	cur := rb.oldest()
+
+	var zero T
+	val := rb.buf[cur]
+	rb.buf[cur] = zero
+	rb.unpin(cur)
+	rb.len -= 1
+	if rb.cost != nil {
+		rb.costTotal -= rb.cost(val)
+	}
+
+	return val
+}
+
 // Take copies up to the n most recent values from the ring buffer into a newly
 // allocated slice, newest-to-oldest, and returns that slice. The ring buffer
 // isn't modified.
@@ -242,3 +841,77 @@ func (rb *RingBuffer[T]) Take(n int) ([]T, error) {
 	}
 	return dst[:n], nil
 }
+
+// TakeTx is like Take, but must be called from within the Do function of the
+// given transaction, which must include this ring buffer. It panics otherwise.
+func (rb *RingBuffer[T]) TakeTx(tx *Transaction, n int) ([]T, error) {
+	if !tx.holds(&rb.mtx) {
+		panic("rb: TakeTx called outside of a transaction holding the ring buffer")
+	}
+
+	dst := make([]T, n)
+	return dst[:rb.copy(dst)], nil
+}
+
+// SizeBytes returns the approximate amount of memory held by the ring buffer,
+// in bytes. The backing array is counted in full, as its capacity times the
+// size of T as reported by unsafe.Sizeof. That doesn't include memory that's
+// only referenced by values, e.g. the contents of strings, slices, or pointers.
+// If per is non-nil, it's called for each stored value, and should return the
+// size of any such referenced memory, which is added to the total.
+func (rb *RingBuffer[T]) SizeBytes(per func(T) int) int {
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.sizeBytes(per)
+}
+
+// sizeBytes assumes the lock is held.
+func (rb *RingBuffer[T]) sizeBytes(per func(T) int) int {
+	var zero T
+	size := cap(rb.buf) * int(unsafe.Sizeof(zero))
+
+	if per != nil {
+		for i := range rb.len {
+			size += per(rb.buf[rb.index(i)])
+		}
+	}
+
+	return size
+}
+
+// Stats summarizes the state of a ring buffer.
+type Stats struct {
+	Count     int // number of values currently stored
+	Capacity  int // maximum number of values
+	SizeBytes int // approximate memory held, see SizeBytes
+	Cost      int // total cost of all values, if a cost limit is set
+
+	// Total number of values ever added, i.e. the next sequence number.
+	Added uint64
+
+	// Info attached at construction, see NewRingBufferWithInfo.
+	Info Info
+
+	// Lock contention, if TrackContention is enabled.
+	LockWait  time.Duration // total time spent waiting for the lock
+	Contended int           // number of times the lock was already held
+}
+
+// Stats returns current statistics for the ring buffer. SizeBytes is computed
+// as if by SizeBytes(nil).
+func (rb *RingBuffer[T]) Stats() Stats {
+	rb.lock()
+	defer rb.unlock()
+
+	return Stats{
+		Count:     rb.len,
+		Capacity:  len(rb.buf),
+		SizeBytes: rb.sizeBytes(nil),
+		Cost:      rb.costTotal,
+		Added:     rb.seq,
+		Info:      rb.Info(),
+		LockWait:  rb.lockWait,
+		Contended: rb.contended,
+	}
+}
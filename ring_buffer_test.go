@@ -1,8 +1,11 @@
 package rb_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -189,6 +192,221 @@ func TestRingBufferIter(t *testing.T) {
 	})
 }
 
+func TestRingBufferWalkReentrant(t *testing.T) {
+	// Not parallel: reentrant calls are only detected when no other goroutine
+	// is in a walk at the same time.
+
+	a, b, c := rb.NewRingBuffer[int](3), rb.NewRingBuffer[int](3), rb.NewRingBuffer[int](3)
+	a.Add(1)
+	b.Add(2)
+
+	// Methods on the walked ring buffer panic, rather than deadlock.
+	var perr *rb.PanicError
+	for _, fn := range []func() error{
+		func() error { a.Add(10); return nil },
+		func() error { _, err := a.Take(1); return err },
+		func() error { return a.Walk(func(int) error { return nil }) },
+	} {
+		err := a.Walk(func(int) error { return fn() })
+		assertEqual(t, true, errors.As(err, &perr))
+		assertEqual(t, true, strings.Contains(fmt.Sprint(perr.Value), "within a walk"))
+	}
+	assertEqual(t, []int{1}, slices.Collect(a.All()))
+
+	// Other ring buffers can be used, and walked, from within a walk, and
+	// every walked ring buffer still panics.
+	err := a.Walk(func(int) error {
+		return b.Walk(func(v int) error {
+			c.Add(v * 10)
+			a.Clear()
+			return nil
+		})
+	})
+	assertEqual(t, true, errors.As(err, &perr))
+	assertEqual(t, []int{1}, slices.Collect(a.All()))
+	assertEqual(t, []int{20}, slices.Collect(c.All()))
+
+	// Calls on a ring buffer walked by another goroutine wait for that walk.
+	walking, release, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go func() {
+		b.Walk(func(int) error { close(walking); <-release; return nil })
+		close(done)
+	}()
+	<-walking
+	err = a.Walk(func(v int) error {
+		close(release)
+		b.Add(v + 1)
+		return nil
+	})
+	<-done
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []int{2, 2}, slices.Collect(b.All()))
+}
+
+func TestRingBufferWalkPanic(t *testing.T) {
+	t.Parallel()
+
+	var perr *rb.PanicError
+
+	rb := rb.NewRingBuffer[int](5)
+	rb.Add(1)
+	rb.Add(2)
+
+	errBoom := errors.New("boom")
+	err := rb.Walk(func(i int) error { panic(errBoom) })
+
+	if !errors.As(err, &perr) {
+		t.Fatalf("want panic error, have %v", err)
+	}
+	assertEqual(t, true, errors.Is(err, errBoom))
+	assertEqual(t, true, strings.Contains(string(perr.Stack), "TestRingBufferWalkPanic"))
+
+	// The lock was released, so the ring buffer is still usable.
+	rb.Add(3)
+	vals, _ := rb.Take(5)
+	assertEqual(t, []int{3, 2, 1}, vals)
+}
+
+func TestRingBufferAddPanic(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](2)
+	rb.SetCostLimit(func(i int) int {
+		if i < 0 {
+			panic("negative")
+		}
+		return i
+	}, 100)
+
+	assertPanics(t, func() { rb.Add(-1) })
+
+	// The lock was released, so the ring buffer is still usable.
+	rb.SetCostLimit(nil, 0)
+	rb.Add(1)
+	vals, _ := rb.Take(5)
+	assertEqual(t, []int{1, -1}, vals)
+}
+
+func TestRingBufferWalkWith(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[string](5)
+	buf.Add("user:1")
+	buf.Add("user:2")
+	buf.Add("user:3")
+
+	names := map[string]string{"user:1": "alice", "user:2": "bob"}
+	errUnknown := errors.New("unknown user")
+	enrich := func(ctx context.Context, id string) (string, error) {
+		name, ok := names[id]
+		if !ok {
+			return "", errUnknown
+		}
+		return name, nil
+	}
+
+	var have []string
+	collect := func(name string) error { have = append(have, name); return nil }
+
+	err := buf.WalkWith(context.Background(), enrich, collect)
+	assertEqual(t, true, errors.Is(err, errUnknown))
+	assertEqual(t, 0, len(have))
+
+	names["user:3"] = "carol"
+	err = buf.WalkWith(context.Background(), enrich, collect)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []string{"carol", "bob", "alice"}, have)
+
+	// The raw values are unchanged.
+	vals, _ := buf.Take(5)
+	assertEqual(t, []string{"user:3", "user:2", "user:1"}, vals)
+}
+
+func TestRingBufferAddIf(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[string](3)
+	changed := func(val string) func(string, int) bool {
+		return func(newest string, count int) bool { return count == 0 || newest != val }
+	}
+
+	assertEqual(t, true, buf.AddIf("up", changed("up")))
+	assertEqual(t, false, buf.AddIf("up", changed("up")))
+	assertEqual(t, true, buf.AddIf("down", changed("down")))
+	assertEqual(t, true, buf.AddIf("up", changed("up")))
+
+	vals, _ := buf.Take(5)
+	assertEqual(t, []string{"up", "down", "up"}, vals)
+
+	// Rejected values aren't added.
+	buf.SetEvictionPolicy(rb.RejectNew[string]())
+	assertEqual(t, false, buf.AddIf("down", changed("down")))
+}
+
+func TestRingBufferWalkContext(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](5)
+	for i := range 5 {
+		rb.Add(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var have []int
+	err := rb.WalkContext(ctx, func(i int) error {
+		have = append(have, i)
+		if i == 3 {
+			cancel()
+		}
+		return nil
+	})
+	assertEqual(t, true, errors.Is(err, context.Canceled))
+	assertEqual(t, []int{4, 3}, have)
+
+	have = have[:0]
+	err = rb.WalkContext(context.Background(), func(i int) error { have = append(have, i); return nil })
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []int{4, 3, 2, 1, 0}, have)
+}
+
+func TestRingBufferWalkChunks(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](5)
+
+	chunks := func() [][]int {
+		res := [][]int{}
+		rb.WalkChunks(func(chunk []int) error {
+			res = append(res, append([]int(nil), chunk...))
+			return nil
+		})
+		return res
+	}
+
+	assertEqual(t, [][]int{}, chunks())
+
+	rb.Add(1)
+	rb.Add(2)
+	rb.Add(3)
+	assertEqual(t, [][]int{{1, 2, 3}}, chunks())
+
+	rb.Add(4)
+	rb.Add(5)
+	assertEqual(t, [][]int{{1, 2, 3, 4, 5}}, chunks())
+
+	rb.Add(6)
+	rb.Add(7)
+	assertEqual(t, [][]int{{3, 4, 5}, {6, 7}}, chunks())
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := rb.WalkChunks(func([]int) error { calls++; return errStop })
+	assertEqual(t, true, errors.Is(err, errStop))
+	assertEqual(t, 1, calls)
+}
+
 func TestRingBufferCopyTake(t *testing.T) {
 	rb := rb.NewRingBuffer[int](32)
 	rb.Add(1)
@@ -330,6 +548,38 @@ func TestRingBufferOverview(t *testing.T) {
 	}
 }
 
+func TestRingBufferOverviewN(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](10)
+
+	newest, oldest, n := rb.OverviewN(3)
+	assertEqual(t, []int{}, newest)
+	assertEqual(t, []int{}, oldest)
+	assertEqual(t, 0, n)
+
+	rb.Add(1)
+	rb.Add(2)
+
+	newest, oldest, n = rb.OverviewN(3)
+	assertEqual(t, []int{2, 1}, newest)
+	assertEqual(t, []int{2, 1}, oldest)
+	assertEqual(t, 2, n)
+
+	for i := 3; i <= 15; i++ {
+		rb.Add(i)
+	}
+
+	newest, oldest, n = rb.OverviewN(3)
+	assertEqual(t, []int{15, 14, 13}, newest)
+	assertEqual(t, []int{8, 7, 6}, oldest)
+	assertEqual(t, 10, n)
+
+	newest, oldest, _ = rb.OverviewN(-1)
+	assertEqual(t, []int{}, newest)
+	assertEqual(t, []int{}, oldest)
+}
+
 func TestRingBufferResize(t *testing.T) {
 	t.Parallel()
 
@@ -375,6 +625,37 @@ func TestRingBufferResize(t *testing.T) {
 	assertEqual(t, 0, len(rb.Resize(-1)))
 }
 
+func TestRingBufferResizeInPlace(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](8)
+	for i := range 13 {
+		rb.Add(i) // wraps around
+	}
+
+	// Shrinking rearranges values in place.
+	assertEqual(t, []int{10, 9, 8, 7, 6, 5}, rb.Resize(2))
+	assertEqual(t, []int{12, 11}, rb.Published())
+	assertEqual(t, 64, rb.SizeBytes(nil))
+
+	// Growing within the original capacity reuses the backing array.
+	rb.Resize(8)
+	rb.Resize(4)
+	assertEqual(t, []int{12, 11}, rb.Published())
+	assertEqual(t, 64, rb.SizeBytes(nil))
+
+	for i := 13; i < 20; i++ {
+		rb.Add(i)
+	}
+	assertEqual(t, []int{19, 18, 17, 16}, rb.Published())
+
+	// Growing beyond the original capacity reallocates.
+	rb.Resize(10)
+	rb.Add(20)
+	assertEqual(t, []int{20, 19, 18, 17, 16}, rb.Published())
+	assertEqual(t, 80, rb.SizeBytes(nil))
+}
+
 func TestRingBufferClear(t *testing.T) {
 	t.Parallel()
 
@@ -404,6 +685,23 @@ func TestRingBufferClear(t *testing.T) {
 	assertEqual(t, []int{20, 10}, vals)
 }
 
+func TestRingBufferSizeBytes(t *testing.T) {
+	t.Parallel()
+
+	ints := rb.NewRingBuffer[int64](10)
+	assertEqual(t, 80, ints.SizeBytes(nil))
+	ints.Add(1)
+	assertEqual(t, 80, ints.SizeBytes(nil))
+	assertEqual(t, rb.Stats{Count: 1, Capacity: 10, SizeBytes: 80, Added: 1}, ints.Stats())
+
+	strs := rb.NewRingBuffer[string](4)
+	strs.Add("abc")
+	strs.Add("defgh")
+	strlen := func(s string) int { return len(s) }
+	assertEqual(t, 4*16, strs.SizeBytes(nil))
+	assertEqual(t, 4*16+3+5, strs.SizeBytes(strlen))
+}
+
 func BenchmarkRingBuffer(b *testing.B) {
 	for _, sz := range []int{100, 1_000, 10_000, 100_000, 1_000_000} {
 		b.Run(fmt.Sprintf("sz=%d", sz), func(b *testing.B) {
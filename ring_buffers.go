@@ -2,14 +2,27 @@ package rb
 
 import (
 	"maps"
+	"slices"
 	"sync"
+	"time"
 )
 
 // RingBuffers collects ring buffers by string category.
 type RingBuffers[T any] struct {
-	mtx  sync.Mutex
-	sz   int
-	bufs map[string]*RingBuffer[T]
+	mtx    sync.Mutex
+	sz     int
+	budget int // max total capacity across all buffers, 0 means unlimited
+	bufs   map[string]*RingBuffer[T]
+
+	emptyTTL   time.Duration        // see RemoveEmptyAfter
+	emptySince map[string]time.Time // when each category was first seen empty
+	lastSweep  time.Time            // of empty categories
+	now        func() time.Time     // see SetClock
+
+	sizes      map[string]int // per-category sizes, see Configure
+	resized    map[string]int // per-category sizes, see ResizeEach
+	ttl        time.Duration  // for new ring buffers, see Configure
+	sampleRate float64        // for new ring buffers, see Configure
 }
 
 // NewRingBuffers returns an empty set of ring buffers, each of which will have
@@ -22,15 +35,22 @@ func NewRingBuffers[T any](sz int) *RingBuffers[T] {
 }
 
 // GetOrCreate returns a ring buffer for the given category string. Once a ring
-// buffer is created in this way, it will always exist.
+// buffer is created in this way, it will always exist, unless garbage
+// collection of empty categories is enabled, see RemoveEmptyAfter.
+//
+// If a budget is set, and creating the ring buffer exceeds that budget, the
+// largest ring buffers are shrunk, and any dropped values are discarded.
 func (rbs *RingBuffers[T]) GetOrCreate(category string) *RingBuffer[T] {
 	rbs.mtx.Lock()
 	defer rbs.mtx.Unlock()
 
+	rbs.collectEmpty()
+
 	rb, ok := rbs.bufs[category]
 	if !ok {
-		rb = NewRingBuffer[T](rbs.sz)
+		rb = rbs.newRingBuffer(rbs.sizeFor(category))
 		rbs.bufs[category] = rb
+		rbs.enforceBudget()
 	}
 
 	return rb
@@ -41,6 +61,8 @@ func (rbs *RingBuffers[T]) GetAll() map[string]*RingBuffer[T] {
 	rbs.mtx.Lock()
 	defer rbs.mtx.Unlock()
 
+	rbs.collectEmpty()
+
 	all := make(map[string]*RingBuffer[T], len(rbs.bufs))
 	maps.Copy(all, rbs.bufs)
 
@@ -59,15 +81,128 @@ func (rbs *RingBuffers[T]) Resize(sz int) (dropped map[string][]T) {
 	defer rbs.mtx.Unlock()
 
 	rbs.sz = sz
+	rbs.resized = nil
 
 	dropped = map[string][]T{}
 	for name, rb := range rbs.bufs {
 		dropped[name] = append(dropped[name], rb.Resize(sz)...)
 	}
+	for name, vals := range rbs.enforceBudget() {
+		dropped[name] = append(dropped[name], vals...)
+	}
+
+	return dropped
+}
+
+// ResizeEach resizes the ring buffers for the given categories, each to its own
+// size, in a single operation under the lock, and returns the dropped values
+// by category. Categories which don't exist yet are created with the given
+// size, and sizes <= 0 are ignored. Other categories are unaffected, and new
+// categories are still created with the size of the set. The sizes are kept
+// by Configure, unless it sets sizes for the same categories.
+func (rbs *RingBuffers[T]) ResizeEach(sizes map[string]int) (dropped map[string][]T) {
+	rbs.mtx.Lock()
+	defer rbs.mtx.Unlock()
+
+	dropped = map[string][]T{}
+	for name, sz := range sizes {
+		if sz <= 0 {
+			continue
+		}
+		if rbs.resized == nil {
+			rbs.resized = map[string]int{}
+		}
+		rbs.resized[name] = sz
+		rb, ok := rbs.bufs[name]
+		if !ok {
+			rbs.bufs[name] = rbs.newRingBuffer(sz)
+			continue
+		}
+		if vals := rb.Resize(sz); len(vals) > 0 {
+			dropped[name] = vals
+		}
+	}
+	for name, vals := range rbs.enforceBudget() {
+		dropped[name] = append(dropped[name], vals...)
+	}
+
+	return dropped
+}
+
+// SetBudget caps the total capacity of all ring buffers in the set, i.e. the
+// maximum number of values stored across all categories. Whenever the budget
+// is exceeded, the largest ring buffers are shrunk as little as possible to fit
+// within it, though every ring buffer retains a capacity of at least 1. Unlike
+// Resize, shrinking for the budget allocates a new, smaller backing array, so
+// the memory held by the old one can be freed. Values dropped as a result of setting the budget are returned by category. If
+// budget <= 0, any existing budget is removed.
+func (rbs *RingBuffers[T]) SetBudget(budget int) (dropped map[string][]T) {
+	rbs.mtx.Lock()
+	defer rbs.mtx.Unlock()
+
+	rbs.budget = max(0, budget)
+
+	return rbs.enforceBudget()
+}
+
+// enforceBudget assumes the lock is held.
+func (rbs *RingBuffers[T]) enforceBudget() (dropped map[string][]T) {
+	if rbs.budget <= 0 {
+		return nil
+	}
+
+	// Sort the buffers by capacity, smallest first.
+	type capacity struct {
+		name string
+		cap  int
+	}
+	caps := make([]capacity, 0, len(rbs.bufs))
+	for name, rb := range rbs.bufs {
+		rb.lock()
+		caps = append(caps, capacity{name, len(rb.buf)})
+		rb.unlock()
+	}
+	slices.SortFunc(caps, func(a, b capacity) int { return a.cap - b.cap })
+
+	// Find the largest limit such that capping each buffer to that limit fits
+	// within the budget. Buffers smaller than their fair share of the remaining
+	// budget are left alone, and their unused share goes to larger buffers.
+	limit, remaining := -1, rbs.budget
+	for i, c := range caps {
+		share := remaining / (len(caps) - i)
+		if c.cap > share {
+			limit = max(1, share)
+			break
+		}
+		remaining -= c.cap
+	}
+	if limit < 0 {
+		return nil // everything fits
+	}
+
+	dropped = map[string][]T{}
+	for _, c := range caps {
+		if c.cap > limit {
+			dropped[c.name] = rbs.bufs[c.name].shrink(limit)
+		}
+	}
 
 	return dropped
 }
 
+// shrink is like Resize, but always allocates a new backing array, see
+// SetBudget.
+func (rb *RingBuffer[T]) shrink(sz int) (dropped []T) {
+	op := rb.startOp("Resize")
+	defer op.done()
+
+	rb.lock()
+	defer rb.unlock()
+	op.acquired()
+
+	return rb.reallocate(sz)
+}
+
 // Clear drops all elements from every ring buffer in the set, returning dropped
 // values for each ring buffer by category. The ring buffers themselves are
 // retained with their existing capacity.
@@ -82,3 +217,25 @@ func (rbs *RingBuffers[T]) Clear() map[string][]T {
 
 	return dropped
 }
+
+// Stats returns statistics for each ring buffer by category, as well as the
+// total of those statistics across all categories.
+func (rbs *RingBuffers[T]) Stats() (total Stats, categories map[string]Stats) {
+	rbs.mtx.Lock()
+	defer rbs.mtx.Unlock()
+
+	categories = make(map[string]Stats, len(rbs.bufs))
+	for name, rb := range rbs.bufs {
+		stats := rb.Stats()
+		total.Count += stats.Count
+		total.Capacity += stats.Capacity
+		total.SizeBytes += stats.SizeBytes
+		total.Cost += stats.Cost
+		total.Added += stats.Added
+		total.LockWait += stats.LockWait
+		total.Contended += stats.Contended
+		categories[name] = stats
+	}
+
+	return total, categories
+}
@@ -66,3 +66,77 @@ func TestRingBuffersClear(t *testing.T) {
 	foo.Walk(func(i int) error { have = append(have, i); return nil })
 	assertEqual(t, ([]int)(nil), have)
 }
+
+func TestRingBuffersStats(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int64](5)
+	rbs.GetOrCreate("foo").Add(1)
+	rbs.GetOrCreate("bar").Add(2)
+	rbs.GetOrCreate("bar").Add(3)
+
+	total, categories := rbs.Stats()
+	assertEqual(t, rb.Stats{Count: 3, Capacity: 10, SizeBytes: 80, Added: 3}, total)
+	assertEqual(t, map[string]rb.Stats{
+		"foo": {Count: 1, Capacity: 5, SizeBytes: 40, Added: 1},
+		"bar": {Count: 2, Capacity: 5, SizeBytes: 40, Added: 2},
+	}, categories)
+}
+
+func TestRingBuffersBudget(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int](10)
+
+	foo := rbs.GetOrCreate("foo")
+	for i := range 10 {
+		foo.Add(i)
+	}
+
+	bar := rbs.GetOrCreate("bar")
+	bar.Resize(2)
+	bar.Add(100)
+	bar.Add(200)
+
+	// 12 total capacity, budget of 8 means foo shrinks to 6, bar is untouched.
+	dropped := rbs.SetBudget(8)
+	assertEqual(t, map[string][]int{"foo": {3, 2, 1, 0}}, dropped)
+	assertEqual(t, 6, foo.Stats().Capacity)
+	assertEqual(t, 2, bar.Stats().Capacity)
+
+	// Shrinking for the budget frees memory, unlike Resize.
+	assertEqual(t, 48, foo.SizeBytes(nil)) // 6 ints
+	vals, _ := foo.Take(10)
+	assertEqual(t, []int{9, 8, 7, 6, 5, 4}, vals)
+
+	// A new category has to fit into the budget, too.
+	baz := rbs.GetOrCreate("baz")
+	total, _ := rbs.Stats()
+	assertEqual(t, 8, total.Capacity)
+	assertEqual(t, 3, foo.Stats().Capacity)
+	assertEqual(t, 2, bar.Stats().Capacity)
+	assertEqual(t, 3, baz.Stats().Capacity)
+
+	// Removing the budget doesn't grow anything.
+	assertEqual(t, map[string][]int(nil), rbs.SetBudget(0))
+	assertEqual(t, 3, foo.Stats().Capacity)
+}
+
+func TestRingBuffersResizeEach(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int](3)
+	for _, v := range []int{1, 2, 3} {
+		rbs.GetOrCreate("foo").Add(v)
+		rbs.GetOrCreate("bar").Add(v)
+	}
+
+	dropped := rbs.ResizeEach(map[string]int{"foo": 1, "baz": 10, "bar": 0})
+	assertEqual(t, map[string][]int{"foo": {2, 1}}, dropped)
+
+	_, categories := rbs.Stats()
+	assertEqual(t, 1, categories["foo"].Capacity)
+	assertEqual(t, 3, categories["bar"].Capacity)
+	assertEqual(t, 10, categories["baz"].Capacity)
+	assertEqual(t, 3, rbs.GetOrCreate("new").Stats().Capacity)
+}
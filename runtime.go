@@ -0,0 +1,139 @@
+package rb
+
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"time"
+)
+
+// DefaultRuntimeMetrics are the runtime/metrics sampled by a RuntimeSampler if
+// none are given: heap size, GC pauses, and goroutines.
+var DefaultRuntimeMetrics = []string{
+	"/memory/classes/heap/objects:bytes",
+	"/sched/pauses/total/gc:seconds",
+	"/sched/goroutines:goroutines",
+}
+
+// RuntimeSampler periodically reads runtime/metrics into a ring buffer per
+// metric, in a set of ring buffers whose categories are the metric names, so
+// e.g. the last hour of runtime health is available without an external time
+// series database. Timestamps are enabled on every ring buffer, so methods
+// like Between can be used to select samples by time.
+//
+// Values of cumulative and gauge metrics are recorded as they are. Histogram
+// metrics, e.g. GC pauses, are recorded as the largest value observed since the
+// previous sample, as the upper bound of its bucket, or 0 if there were none.
+// Metrics which aren't supported by the runtime are ignored.
+type RuntimeSampler struct {
+	interval time.Duration
+	rbs      *RingBuffers[float64]
+	samples  []metrics.Sample
+	prev     map[string][]uint64 // previous histogram counts, by name
+}
+
+// NewRuntimeSampler returns a runtime sampler which samples the given metrics,
+// or DefaultRuntimeMetrics if none are given, at every interval, and keeps
+// samples for the given retention, e.g. 10s and 1h. If interval isn't
+// positive, 10s is used.
+func NewRuntimeSampler(interval, retention time.Duration, names ...string) *RuntimeSampler {
+	if len(names) == 0 {
+		names = DefaultRuntimeMetrics
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	supported := map[string]bool{}
+	for _, d := range metrics.All() {
+		supported[d.Name] = true
+	}
+
+	s := &RuntimeSampler{
+		interval: interval,
+		rbs:      NewRingBuffers[float64](max(1, int(retention/interval))),
+		prev:     map[string][]uint64{},
+	}
+	for _, name := range names {
+		if !supported[name] {
+			continue
+		}
+		s.samples = append(s.samples, metrics.Sample{Name: name})
+		s.rbs.GetOrCreate(name).EnableTimestamps(nil)
+	}
+
+	// Seed the histogram counts, so the first sample of a histogram covers only
+	// the first interval, rather than everything since the process started.
+	metrics.Read(s.samples)
+	for _, sample := range s.samples {
+		if sample.Value.Kind() == metrics.KindFloat64Histogram {
+			s.prev[sample.Name] = append([]uint64(nil), sample.Value.Float64Histogram().Counts...)
+		}
+	}
+
+	return s
+}
+
+// RingBuffers returns the ring buffers of samples, by metric name.
+func (s *RuntimeSampler) RingBuffers() *RingBuffers[float64] {
+	return s.rbs
+}
+
+// Run samples the metrics at every interval, until the context is done.
+func (s *RuntimeSampler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.Sample()
+		}
+	}
+}
+
+// Sample the metrics once. It's not safe to call Sample concurrently,
+// including with Run.
+func (s *RuntimeSampler) Sample() {
+	metrics.Read(s.samples)
+
+	for _, sample := range s.samples {
+		var v float64
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			v = float64(sample.Value.Uint64())
+		case metrics.KindFloat64:
+			v = sample.Value.Float64()
+		case metrics.KindFloat64Histogram:
+			v = s.histogramMax(sample.Name, sample.Value.Float64Histogram())
+		default:
+			continue
+		}
+		s.rbs.GetOrCreate(sample.Name).Add(v)
+	}
+}
+
+// histogramMax returns the upper bound of the highest bucket whose count has
+// increased since the previous sample, or its lower bound if the upper bound is
+// infinite, or 0 if no counts have increased.
+func (s *RuntimeSampler) histogramMax(name string, h *metrics.Float64Histogram) float64 {
+	prev := s.prev[name]
+	defer func() { s.prev[name] = append(prev[:0], h.Counts...) }()
+
+	for i := len(h.Counts) - 1; i >= 0; i-- {
+		var before uint64
+		if i < len(prev) {
+			before = prev[i]
+		}
+		if h.Counts[i] <= before {
+			continue
+		}
+		if upper := h.Buckets[i+1]; !math.IsInf(upper, 1) {
+			return upper
+		}
+		return h.Buckets[i]
+	}
+	return 0
+}
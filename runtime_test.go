@@ -0,0 +1,43 @@
+package rb_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRuntimeSampler(t *testing.T) {
+	t.Parallel()
+
+	s := rb.NewRuntimeSampler(time.Second, time.Minute)
+	s.Sample()
+	runtime.GC()
+	s.Sample()
+	s.Sample()
+
+	all := s.RingBuffers().GetAll()
+	assertEqual(t, len(rb.DefaultRuntimeMetrics), len(all))
+
+	goroutines, _ := all["/sched/goroutines:goroutines"].Take(10)
+	assertEqual(t, 3, len(goroutines))
+	assertEqual(t, true, goroutines[0] > 0)
+
+	heap, _, _ := all["/memory/classes/heap/objects:bytes"].Overview()
+	assertEqual(t, true, heap > 0)
+
+	// The forced GC paused.
+	pauses, _ := all["/sched/pauses/total/gc:seconds"].Take(10)
+	assertEqual(t, true, pauses[1] > 0)
+	assertEqual(t, 60, all["/sched/pauses/total/gc:seconds"].Stats().Capacity)
+
+	// Unsupported metrics are ignored.
+	s = rb.NewRuntimeSampler(time.Second, time.Minute, "/no/such:metric", "/sched/goroutines:goroutines")
+	s.Sample()
+	assertEqual(t, 1, len(s.RingBuffers().GetAll()))
+
+	// The default interval is 10s.
+	s = rb.NewRuntimeSampler(0, time.Minute)
+	assertEqual(t, 6, s.RingBuffers().GetOrCreate("/sched/goroutines:goroutines").Stats().Capacity)
+}
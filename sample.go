@@ -0,0 +1,27 @@
+package rb
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// SetSampleRate makes Add keep only a random fraction of the values it's
+// given, which reduces the cost of recording high-volume values, at the
+// expense of completeness. Values which aren't sampled are discarded, and
+// aren't returned as dropped. Other methods which add values, e.g. Load, are
+// unaffected. A rate <= 0 or >= 1 keeps every value.
+func (rb *RingBuffer[T]) SetSampleRate(rate float64) {
+	if rate <= 0 || rate >= 1 {
+		rb.sampleRate.Store(0)
+		rb.setFlag(flagSample, false)
+		return
+	}
+	rb.sampleRate.Store(math.Float64bits(rate))
+	rb.setFlag(flagSample, true)
+}
+
+// sample returns true if a value should be kept, according to the sample rate.
+func (rb *RingBuffer[T]) sample() bool {
+	bits := rb.sampleRate.Load()
+	return bits == 0 || rand.Float64() < math.Float64frombits(bits)
+}
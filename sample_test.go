@@ -0,0 +1,25 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestSetSampleRate(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](10)
+	buf.SetSampleRate(0.25)
+	for i := range 10000 {
+		buf.Add(i)
+	}
+
+	// Unsampled values aren't assigned sequence numbers.
+	added := buf.Stats().Added
+	assertEqual(t, true, added > 2000 && added < 3000)
+
+	buf.SetSampleRate(1)
+	buf.Add(-1)
+	assertEqual(t, added+1, buf.Stats().Added)
+}
@@ -0,0 +1,37 @@
+package rb
+
+// At returns the value with the given sequence number, as reported by e.g.
+// SubscribeEntries, if it's still in the ring buffer. Sequence numbers start
+// at 0, and increase by 1 with every value added.
+func (rb *RingBuffer[T]) At(seq uint64) (T, bool) {
+	rb.lock()
+	defer rb.unlock()
+
+	if n := rb.countSince(seq); n > 0 && rb.seqAt(n-1) == seq {
+		return rb.buf[rb.index(n-1)], true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// RangeSeq returns the values with sequence numbers from the given sequence
+// number, inclusive, to the given sequence number, exclusive, which are still
+// in the ring buffer, oldest first.
+func (rb *RingBuffer[T]) RangeSeq(from, to uint64) []T {
+	rb.lock()
+	defer rb.unlock()
+
+	if from >= to {
+		return []T{}
+	}
+
+	newest := rb.countSince(to)       // index of the newest value before to
+	oldest := rb.countSince(from) - 1 // index of the oldest value since from
+
+	vals := make([]T, 0, max(0, oldest-newest+1))
+	for i := oldest; i >= newest; i-- {
+		vals = append(vals, rb.buf[rb.index(i)])
+	}
+	return vals
+}
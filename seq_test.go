@@ -0,0 +1,44 @@
+package rb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestAtRangeSeq(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		setup func(*rb.RingBuffer[string])
+	}{
+		{"default", func(*rb.RingBuffer[string]) {}},
+		{"timestamps", func(buf *rb.RingBuffer[string]) { buf.EnableTimestamps(time.Now) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := rb.NewRingBuffer[string](3)
+			tc.setup(buf)
+			for _, v := range []string{"a", "b", "c", "d", "e"} {
+				buf.Add(v) // seq 0 through 4
+			}
+
+			v, ok := buf.At(3)
+			assertEqual(t, true, ok)
+			assertEqual(t, "d", v)
+
+			_, ok = buf.At(1) // overwritten
+			assertEqual(t, false, ok)
+			_, ok = buf.At(5) // not yet added
+			assertEqual(t, false, ok)
+
+			assertEqual(t, []string{"c", "d"}, buf.RangeSeq(0, 4))
+			assertEqual(t, []string{"d", "e"}, buf.RangeSeq(3, 100))
+			assertEqual(t, []string{}, buf.RangeSeq(0, 2))
+			assertEqual(t, []string{}, buf.RangeSeq(3, 3))
+		})
+	}
+}
@@ -0,0 +1,225 @@
+//go:build unix
+
+package rb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// A shared ring is a ring buffer of fixed-size binary records, stored in a
+// memory-mapped file, so that it can be written by one process, and read by
+// others, e.g. a monitoring agent, without any RPC. For true shared memory, the
+// file should be on a memory-backed filesystem, like /dev/shm on Linux.
+//
+// The layout of the file is as follows, with all integers as uint64 in the
+// native byte order of the host.
+//
+//	offset 0:  magic, the bytes "rbshm001" as a uint64
+//	offset 8:  record size, in bytes
+//	offset 16: capacity, in records
+//	offset 24: sequence number of the next record to be written
+//	offset 32: reserved, up to offset 64
+//	offset 64: capacity slots, each of which is a stamp, followed by the
+//	           record, padded to a multiple of 8 bytes
+//
+// A record with sequence number seq is stored in slot seq % capacity, and its
+// stamp is seq+1 when the record is complete. While a record is being written,
+// its stamp is all ones. Readers check the stamp before and after reading a
+// record, and stop at the first record which is incomplete, or was overwritten
+// during the read. So a writer which crashes in the middle of a write leaves
+// the ring readable, minus the record it was writing.
+const (
+	shmMagic      = 0x3130306d68736272 // "rbshm001" in little endian
+	shmHeaderSize = 64
+	shmBusy       = ^uint64(0)
+)
+
+// SharedRingWriter writes records to a shared ring. There must be at most one
+// writer for a given shared ring. It's safe for concurrent use by multiple
+// goroutines.
+type SharedRingWriter struct {
+	mtx sync.Mutex
+	shm sharedRing
+}
+
+// CreateSharedRing creates a shared ring in the file at path, which is created,
+// or truncated if it already exists, with room for capacity records of
+// recordSize bytes each.
+func CreateSharedRing(path string, recordSize, capacity int) (*SharedRingWriter, error) {
+	if recordSize <= 0 || capacity <= 0 {
+		return nil, errors.New("record size and capacity must be positive")
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // the mapping remains valid
+
+	size := shmHeaderSize + capacity*shmSlotSize(recordSize)
+	if err := f.Truncate(int64(size)); err != nil {
+		return nil, err
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap: %w", err)
+	}
+
+	shm := sharedRing{data: data, recordSize: recordSize, capacity: capacity}
+	atomic.StoreUint64(shm.word(8), uint64(recordSize))
+	atomic.StoreUint64(shm.word(16), uint64(capacity))
+	atomic.StoreUint64(shm.word(24), 0)
+	atomic.StoreUint64(shm.word(0), shmMagic) // last, so readers see a complete header
+
+	return &SharedRingWriter{shm: shm}, nil
+}
+
+// Add writes the record to the shared ring, overwriting the oldest record if
+// the ring is full. Records shorter than the record size are padded with zeros,
+// and longer records return an error.
+func (w *SharedRingWriter) Add(record []byte) error {
+	if len(record) > w.shm.recordSize {
+		return fmt.Errorf("record size %d exceeds %d", len(record), w.shm.recordSize)
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.shm.data == nil {
+		return errors.New("shared ring is closed")
+	}
+
+	seq := atomic.LoadUint64(w.shm.word(24))
+	stamp, body := w.shm.slot(seq)
+
+	atomic.StoreUint64(stamp, shmBusy)
+	n := copy(body, record)
+	clear(body[n:])
+	atomic.StoreUint64(stamp, seq+1)
+	atomic.StoreUint64(w.shm.word(24), seq+1)
+
+	return nil
+}
+
+// Close unmaps the shared ring. The file remains, for readers.
+func (w *SharedRingWriter) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return w.shm.close()
+}
+
+// SharedRingReader reads records from a shared ring, which may be written
+// concurrently by another process.
+type SharedRingReader struct {
+	shm sharedRing
+}
+
+// OpenSharedRing opens the shared ring in the file at path for reading.
+func OpenSharedRing(path string) (*SharedRingReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // the mapping remains valid
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < shmHeaderSize {
+		return nil, errors.New("not a shared ring: file too small")
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap: %w", err)
+	}
+
+	shm := sharedRing{data: data}
+	if atomic.LoadUint64(shm.word(0)) != shmMagic {
+		shm.close()
+		return nil, errors.New("not a shared ring: bad magic")
+	}
+	shm.recordSize = int(atomic.LoadUint64(shm.word(8)))
+	shm.capacity = int(atomic.LoadUint64(shm.word(16)))
+	if shm.recordSize <= 0 || shm.capacity <= 0 || shmHeaderSize+shm.capacity*shmSlotSize(shm.recordSize) > len(data) {
+		shm.close()
+		return nil, errors.New("not a shared ring: bad header")
+	}
+
+	return &SharedRingReader{shm: shm}, nil
+}
+
+// RecordSize returns the size of each record, in bytes.
+func (r *SharedRingReader) RecordSize() int {
+	return r.shm.recordSize
+}
+
+// Take returns up to n of the most recent records, newest first, each of which
+// is a copy. Records which are being written, or which are overwritten while
+// they're being read, end the result early.
+func (r *SharedRingReader) Take(n int) [][]byte {
+	next := atomic.LoadUint64(r.shm.word(24))
+	n = int(min(uint64(max(0, n)), uint64(r.shm.capacity), next))
+
+	records := make([][]byte, 0, n)
+	for i := range n {
+		seq := next - 1 - uint64(i)
+		stamp, body := r.shm.slot(seq)
+
+		if atomic.LoadUint64(stamp) != seq+1 {
+			break
+		}
+		record := append([]byte(nil), body...)
+		if atomic.LoadUint64(stamp) != seq+1 {
+			break
+		}
+
+		records = append(records, record)
+	}
+	return records
+}
+
+// Close unmaps the shared ring.
+func (r *SharedRingReader) Close() error {
+	return r.shm.close()
+}
+
+// sharedRing is the memory-mapped file, common to readers and writers.
+type sharedRing struct {
+	data       []byte
+	recordSize int
+	capacity   int
+}
+
+func shmSlotSize(recordSize int) int {
+	return 8 + (recordSize+7)/8*8
+}
+
+// word returns the uint64 at the given offset, which must be a multiple of 8.
+func (s *sharedRing) word(offset int) *uint64 {
+	return (*uint64)(unsafe.Pointer(&s.data[offset]))
+}
+
+// slot returns the stamp and the record body for the given sequence number.
+func (s *sharedRing) slot(seq uint64) (*uint64, []byte) {
+	offset := shmHeaderSize + int(seq%uint64(s.capacity))*shmSlotSize(s.recordSize)
+	return s.word(offset), s.data[offset+8 : offset+8+s.recordSize]
+}
+
+func (s *sharedRing) close() error {
+	if s.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(s.data)
+	s.data = nil
+	return err
+}
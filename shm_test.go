@@ -0,0 +1,66 @@
+//go:build unix
+
+package rb_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestSharedRing(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ring")
+
+	w, err := rb.CreateSharedRing(path, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	r, err := rb.OpenSharedRing(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	assertEqual(t, 4, r.RecordSize())
+	assertEqual(t, 0, len(r.Take(10)))
+
+	for _, s := range []string{"a", "bb", "ccc", "dddd"} {
+		if err := w.Add([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Add([]byte("eeeee")); err == nil {
+		t.Errorf("want error for oversized record")
+	}
+
+	// The reader sees the writer's records, padded to the record size.
+	assertEqual(t, [][]byte{[]byte("dddd"), []byte("ccc\x00"), []byte("bb\x00\x00")}, r.Take(10))
+	assertEqual(t, [][]byte{[]byte("dddd")}, r.Take(1))
+
+	// Simulate a writer crash in the middle of writing the next record, which
+	// overwrites the slot of the oldest record. The reader stops before it.
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	slot := int64(64 + (4%3)*(8+8))
+	if _, err := f.WriteAt(bytes.Repeat([]byte{0xff}, 8), slot); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, [][]byte{[]byte("dddd"), []byte("ccc\x00")}, r.Take(10))
+
+	// Files which aren't shared rings are rejected.
+	bad := filepath.Join(t.TempDir(), "bad")
+	os.WriteFile(bad, bytes.Repeat([]byte{1}, 128), 0o644)
+	if _, err := rb.OpenSharedRing(bad); err == nil {
+		t.Errorf("want error for bad file")
+	}
+}
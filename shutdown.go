@@ -0,0 +1,50 @@
+package rb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// FlushOnShutdown waits until the context is done, e.g. a context from
+// signal.NotifyContext, and then drains the ring buffer, passing its values to
+// the sink, newest first, so buffered telemetry isn't lost when the process
+// exits cleanly. It returns the error from the sink, if any. The sink isn't
+// called if the ring buffer is empty. Values are drained whether or not the
+// sink succeeds. Values added after the ring buffer is drained aren't flushed.
+//
+// It blocks, so it's typically run in a goroutine, and the process should wait
+// for it to return before exiting.
+func (rb *RingBuffer[T]) FlushOnShutdown(ctx context.Context, sink func([]T) error) error {
+	<-ctx.Done()
+
+	vals := rb.Clear()
+	if len(vals) == 0 {
+		return nil
+	}
+	return sink(vals)
+}
+
+// FlushOnShutdown is like RingBuffer.FlushOnShutdown, but drains every ring
+// buffer in the set, and passes each category's values to the sink separately,
+// in order of category. All categories are flushed, even if the sink fails for
+// some, and the errors are joined.
+func (rbs *RingBuffers[T]) FlushOnShutdown(ctx context.Context, sink func(category string, vals []T) error) error {
+	<-ctx.Done()
+
+	dropped := rbs.Clear()
+
+	var errs []error
+	for _, category := range slices.Sorted(maps.Keys(dropped)) {
+		vals := dropped[category]
+		if len(vals) == 0 {
+			continue
+		}
+		if err := sink(category, vals); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", category, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferFlushOnShutdown(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](5)
+	buf.Add(1)
+	buf.Add(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	var flushed []int
+	go func() {
+		errc <- buf.FlushOnShutdown(ctx, func(vals []int) error { flushed = vals; return nil })
+	}()
+
+	buf.Add(3)
+	cancel()
+	assertEqual(t, error(nil), <-errc)
+	assertEqual(t, []int{3, 2, 1}, flushed)
+
+	vals, _ := buf.Take(10)
+	assertEqual(t, []int{}, vals)
+
+	// The sink isn't called if there's nothing to flush.
+	err := buf.FlushOnShutdown(ctx, func([]int) error { return errors.New("called") })
+	assertEqual(t, error(nil), err)
+}
+
+func TestRingBuffersFlushOnShutdown(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int](5)
+	rbs.GetOrCreate("a").Add(1)
+	rbs.GetOrCreate("b").Add(2)
+	rbs.GetOrCreate("b").Add(3)
+	rbs.GetOrCreate("c")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errFail := errors.New("fail")
+	flushed := map[string][]int{}
+	err := rbs.FlushOnShutdown(ctx, func(category string, vals []int) error {
+		flushed[category] = vals
+		if category == "a" {
+			return errFail
+		}
+		return nil
+	})
+	assertEqual(t, true, errors.Is(err, errFail))
+	assertEqual(t, "a: fail", err.Error())
+	assertEqual(t, map[string][]int{"a": {1}, "b": {3, 2}}, flushed)
+}
@@ -0,0 +1,187 @@
+package rb
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SlabRingBuffer is a Buffer which stores values encoded by a codec in a single
+// pre-allocated byte slab, with a fixed-size slot per value, and decodes them
+// when they're read. A RingBuffer of values which contain pointers, e.g.
+// strings, gives the garbage collector one or more pointers per value to scan,
+// which is significant for buffers with millions of values. A slab contains no
+// pointers, so it's effectively invisible to the garbage collector.
+//
+// Values are encoded before the lock is taken. Walk decodes each value under
+// the lock, and Take copies the encoded values under the lock, and decodes them
+// after it's released. The codec's Decode method must not retain the data it's
+// given, which may be a slot in the slab.
+//
+// Add can't return an error, so if a value can't be encoded, or its encoding is
+// larger than the slot size, it's not stored, and the error is available via
+// Err. If a stored value can't be decoded, Walk and Take return the error, and
+// other methods return a zero value in its place.
+type SlabRingBuffer[T any] struct {
+	codec    Codec[T]
+	slotSize int
+
+	mtx  sync.Mutex
+	slab []byte   // len(lens) slots of slotSize bytes
+	lens []uint32 // length of the encoded value in each slot
+	cur  int      // index of the next slot to be written
+	len  int      // number of values
+	err  error
+}
+
+var _ Buffer[int] = (*SlabRingBuffer[int])(nil)
+
+// NewSlabRingBuffer returns an empty slab ring buffer of size sz, which stores
+// each value in a slot of slotSize bytes.
+func NewSlabRingBuffer[T any](sz int, codec Codec[T], slotSize int) *SlabRingBuffer[T] {
+	return &SlabRingBuffer[T]{
+		codec:    codec,
+		slotSize: slotSize,
+		slab:     make([]byte, sz*slotSize),
+		lens:     make([]uint32, sz),
+	}
+}
+
+// Err returns the most recent error from encoding a value in Add, if any.
+func (b *SlabRingBuffer[T]) Err() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.err
+}
+
+// Add implements Buffer.
+func (b *SlabRingBuffer[T]) Add(val T) (dropped T, ok bool) {
+	data, err := b.codec.Encode(val)
+	if err == nil && len(data) > b.slotSize {
+		err = fmt.Errorf("encoded value size %d exceeds slot size %d", len(data), b.slotSize)
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if err != nil {
+		b.err = err
+		return dropped, false
+	}
+
+	if len(b.lens) == 0 {
+		return dropped, false
+	}
+
+	if b.len == len(b.lens) {
+		dropped, _ = b.decode(b.cur)
+		ok = true
+	} else {
+		b.len++
+	}
+
+	copy(b.slot(b.cur), data)
+	b.lens[b.cur] = uint32(len(data))
+	b.cur = (b.cur + 1) % len(b.lens)
+
+	return dropped, ok
+}
+
+// Walk implements Buffer.
+func (b *SlabRingBuffer[T]) Walk(fn func(T) error) (err error) {
+	defer recoverPanic(&err)
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for i := range b.len {
+		val, err := b.decode(b.index(i))
+		if err != nil {
+			return err
+		}
+		if err := fn(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Take implements Buffer.
+func (b *SlabRingBuffer[T]) Take(n int) ([]T, error) {
+	b.mtx.Lock()
+	n = min(max(0, n), b.len)
+	datas := make([][]byte, n)
+	for i := range n {
+		datas[i] = append([]byte(nil), b.data(b.index(i))...)
+	}
+	b.mtx.Unlock()
+
+	vals := make([]T, n)
+	for i, data := range datas {
+		var err error
+		if vals[i], err = b.codec.Decode(data); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// Overview implements Buffer.
+func (b *SlabRingBuffer[T]) Overview() (newest, oldest T, count int) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.len == 0 {
+		return newest, oldest, 0
+	}
+	newest, _ = b.decode(b.index(0))
+	oldest, _ = b.decode(b.index(b.len - 1))
+	return newest, oldest, b.len
+}
+
+// Resize implements Buffer. The slab is re-allocated.
+func (b *SlabRingBuffer[T]) Resize(sz int) (dropped []T) {
+	if sz <= 0 {
+		return nil
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for i := sz; i < b.len; i++ {
+		val, _ := b.decode(b.index(i))
+		dropped = append(dropped, val)
+	}
+
+	n := min(sz, b.len)
+	slab, lens := make([]byte, sz*b.slotSize), make([]uint32, sz)
+	for i := range n {
+		src, dst := b.index(i), n-1-i
+		copy(slab[dst*b.slotSize:], b.slot(src))
+		lens[dst] = b.lens[src]
+	}
+
+	b.slab, b.lens, b.len, b.cur = slab, lens, n, n%sz
+	return dropped
+}
+
+// index returns the slot of the ith most recent value. It assumes the lock is
+// held.
+func (b *SlabRingBuffer[T]) index(i int) int {
+	return (b.cur - 1 - i + len(b.lens)) % len(b.lens)
+}
+
+// slot returns the whole slot at index i.
+func (b *SlabRingBuffer[T]) slot(i int) []byte {
+	return b.slab[i*b.slotSize : (i+1)*b.slotSize]
+}
+
+// data returns the encoded value in the slot at index i.
+func (b *SlabRingBuffer[T]) data(i int) []byte {
+	return b.slot(i)[:b.lens[i]]
+}
+
+// decode decodes the value in the slot at index i.
+func (b *SlabRingBuffer[T]) decode(i int) (T, error) {
+	return b.codec.Decode(b.data(i))
+}
@@ -0,0 +1,61 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestSlabRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	b := rb.NewSlabRingBuffer(3, rb.JSONCodec[string]{}, 8)
+	for _, s := range []string{"a", "bb", "ccc"} {
+		_, ok := b.Add(s)
+		assertEqual(t, false, ok)
+	}
+
+	dropped, ok := b.Add("dddd")
+	assertEqual(t, true, ok)
+	assertEqual(t, "a", dropped)
+
+	vals, err := b.Take(10)
+	assertEqual(t, nil, err)
+	assertEqual(t, []string{"dddd", "ccc", "bb"}, vals)
+
+	var walked []string
+	b.Walk(func(s string) error { walked = append(walked, s); return nil })
+	assertEqual(t, vals, walked)
+
+	newest, oldest, count := b.Overview()
+	assertEqual(t, "dddd", newest)
+	assertEqual(t, "bb", oldest)
+	assertEqual(t, 3, count)
+
+	// Values which don't fit in a slot aren't stored.
+	_, ok = b.Add("much too long")
+	assertEqual(t, false, ok)
+	if b.Err() == nil {
+		t.Errorf("Err: want error, have nil")
+	}
+	vals, _ = b.Take(1)
+	assertEqual(t, []string{"dddd"}, vals)
+}
+
+func TestSlabRingBufferResize(t *testing.T) {
+	t.Parallel()
+
+	b := rb.NewSlabRingBuffer(4, rb.BinaryCodec[int64]{}, 8)
+	for i := range int64(6) {
+		b.Add(i)
+	}
+
+	assertEqual(t, []int64{3, 2}, b.Resize(2))
+	vals, _ := b.Take(10)
+	assertEqual(t, []int64{5, 4}, vals)
+
+	assertEqual(t, []int64(nil), b.Resize(4))
+	b.Add(6)
+	vals, _ = b.Take(10)
+	assertEqual(t, []int64{6, 5, 4}, vals)
+}
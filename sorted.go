@@ -0,0 +1,118 @@
+package rb
+
+import (
+	"slices"
+	"sort"
+	"sync"
+)
+
+// SortedEnd identifies which end of a SortedRingBuffer is evicted when it's
+// full.
+type SortedEnd int
+
+const (
+	// EvictSmallest evicts the smallest value, keeping the largest values.
+	EvictSmallest SortedEnd = iota
+
+	// EvictLargest evicts the largest value, keeping the smallest values.
+	EvictLargest
+)
+
+// SortedRingBuffer is a fixed-size collection of values, which are kept ordered
+// by a comparison function at all times. When it's full, adding a value evicts
+// the value at one end of the order, so e.g. the N best scores are retained
+// without repeatedly sorting. Among equal values, the oldest is evicted first.
+//
+// Add is O(log n) to find the position of the value, plus O(n) to insert it.
+// It's safe for concurrent use by multiple goroutines.
+type SortedRingBuffer[T any] struct {
+	mtx   sync.Mutex
+	vals  []T // ordered by cmp, with the evicted end at index 0
+	sz    int
+	cmp   func(a, b T) int
+	evict SortedEnd
+}
+
+// NewSortedRingBuffer returns an empty sorted ring buffer of size sz, ordered
+// by cmp, which returns a negative number if a < b, a positive number if a > b,
+// and 0 if they're equal, like the cmp.Compare function.
+func NewSortedRingBuffer[T any](sz int, cmp func(a, b T) int, evict SortedEnd) *SortedRingBuffer[T] {
+	return &SortedRingBuffer[T]{
+		vals:  make([]T, 0, max(0, sz)),
+		sz:    max(0, sz),
+		cmp:   cmp,
+		evict: evict,
+	}
+}
+
+// Add the value in its place in the order. If a value was evicted, which may
+// be the given value, return it and true; otherwise, return a zero value and
+// false.
+func (srb *SortedRingBuffer[T]) Add(val T) (dropped T, ok bool) {
+	srb.mtx.Lock()
+	defer srb.mtx.Unlock()
+
+	if srb.sz == 0 {
+		return val, true
+	}
+
+	// Values are stored so that index 0 is evicted first, and equal values are
+	// ordered oldest first, so the new value goes after any equal values.
+	i := sort.Search(len(srb.vals), func(i int) bool { return srb.less(val, srb.vals[i]) })
+
+	if len(srb.vals) >= srb.sz {
+		if i == 0 {
+			return val, true
+		}
+		dropped, ok = srb.vals[0], true
+		srb.vals = slices.Delete(srb.vals, 0, 1)
+		i--
+	}
+
+	srb.vals = slices.Insert(srb.vals, i, val)
+	return dropped, ok
+}
+
+// Take returns up to n values, starting with the value furthest from eviction,
+// e.g. the largest value if the smallest values are evicted.
+func (srb *SortedRingBuffer[T]) Take(n int) []T {
+	srb.mtx.Lock()
+	defer srb.mtx.Unlock()
+
+	vals := make([]T, min(max(0, n), len(srb.vals)))
+	for i := range vals {
+		vals[i] = srb.vals[len(srb.vals)-1-i]
+	}
+	return vals
+}
+
+// Walk calls the given function for each value, in the same order as Take,
+// until it returns an error. The lock is held during the walk, so the function
+// must not call other methods on the same sorted ring buffer.
+func (srb *SortedRingBuffer[T]) Walk(fn func(T) error) error {
+	srb.mtx.Lock()
+	defer srb.mtx.Unlock()
+
+	for _, val := range slices.Backward(srb.vals) {
+		if err := fn(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of values.
+func (srb *SortedRingBuffer[T]) Len() int {
+	srb.mtx.Lock()
+	defer srb.mtx.Unlock()
+
+	return len(srb.vals)
+}
+
+// less reports whether a is closer to eviction than b.
+func (srb *SortedRingBuffer[T]) less(a, b T) bool {
+	if srb.evict == EvictLargest {
+		return srb.cmp(a, b) > 0
+	}
+	return srb.cmp(a, b) < 0
+}
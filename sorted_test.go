@@ -0,0 +1,49 @@
+package rb_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestSortedRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	type score struct {
+		Name  string
+		Score int
+	}
+	byScore := func(a, b score) int { return cmp.Compare(a.Score, b.Score) }
+
+	best := rb.NewSortedRingBuffer(3, byScore, rb.EvictSmallest)
+	for _, s := range []score{{"a", 5}, {"b", 9}, {"c", 1}} {
+		_, ok := best.Add(s)
+		assertEqual(t, false, ok)
+	}
+
+	dropped, ok := best.Add(score{"d", 7})
+	assertEqual(t, true, ok)
+	assertEqual(t, score{"c", 1}, dropped)
+
+	// Values smaller than every existing value are rejected.
+	dropped, ok = best.Add(score{"e", 2})
+	assertEqual(t, true, ok)
+	assertEqual(t, score{"e", 2}, dropped)
+
+	// Among equal values, the oldest is evicted.
+	dropped, ok = best.Add(score{"f", 5})
+	assertEqual(t, true, ok)
+	assertEqual(t, score{"a", 5}, dropped)
+
+	assertEqual(t, []score{{"b", 9}, {"d", 7}, {"f", 5}}, best.Take(10))
+	assertEqual(t, 3, best.Len())
+
+	worst := rb.NewSortedRingBuffer(2, cmp.Compare[int], rb.EvictLargest)
+	for _, v := range []int{5, 3, 8, 1} {
+		worst.Add(v)
+	}
+	var walked []int
+	worst.Walk(func(v int) error { walked = append(walked, v); return nil })
+	assertEqual(t, []int{1, 3}, walked)
+}
@@ -0,0 +1,85 @@
+package rb
+
+import (
+	"time"
+)
+
+// SpanSummary is a dependency-free summary of a completed trace span.
+type SpanSummary struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string // empty for root spans
+	Service      string
+	Operation    string
+	Start        time.Time
+	Duration     time.Duration
+	Error        bool
+	Attributes   map[string]string
+}
+
+// Category returns the category used to buffer the span, "service/operation".
+func (s SpanSummary) Category() string {
+	return s.Service + "/" + s.Operation
+}
+
+// TailSampler buffers recently completed spans by category, and makes a tail
+// sampling decision for each trace when its root span completes. It's designed
+// to be the backend of an OpenTelemetry span processor: convert each ended span
+// to a SpanSummary in the processor's OnEnd method, and pass it to OnEnd here.
+//
+// Spans of sampled traces are passed to the export function. Spans of traces
+// which aren't sampled, or whose root span never completes, remain in the ring
+// buffers until they're overwritten, so they're still available for debugging.
+//
+// It's safe for concurrent use by multiple goroutines.
+type TailSampler struct {
+	spans  *RingBuffers[SpanSummary]
+	decide func(trace []SpanSummary) bool
+	export func(trace []SpanSummary)
+}
+
+// NewTailSampler returns a tail sampler which buffers up to sz spans in each
+// category. When a root span completes, all of the buffered spans of its trace
+// are passed to decide, and if it returns true, to export.
+func NewTailSampler(sz int, decide func(trace []SpanSummary) bool, export func(trace []SpanSummary)) *TailSampler {
+	return &TailSampler{
+		spans:  NewRingBuffers[SpanSummary](sz),
+		decide: decide,
+		export: export,
+	}
+}
+
+// OnEnd records a completed span. If it's a root span, the sampling decision
+// for its trace is made synchronously.
+func (s *TailSampler) OnEnd(span SpanSummary) {
+	s.spans.GetOrCreate(span.Category()).Add(span)
+
+	if span.ParentSpanID != "" {
+		return
+	}
+
+	trace := s.Trace(span.TraceID)
+	if s.decide(trace) {
+		s.export(trace)
+	}
+}
+
+// Trace returns all buffered spans with the given trace ID, in no particular
+// order.
+func (s *TailSampler) Trace(traceID string) []SpanSummary {
+	var trace []SpanSummary
+	for _, rb := range s.spans.GetAll() {
+		rb.Walk(func(span SpanSummary) error {
+			if span.TraceID == traceID {
+				trace = append(trace, span)
+			}
+			return nil
+		})
+	}
+	return trace
+}
+
+// Spans returns the underlying ring buffers, by category.
+func (s *TailSampler) Spans() *RingBuffers[SpanSummary] {
+	return s.spans
+}
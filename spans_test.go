@@ -0,0 +1,46 @@
+package rb_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestTailSampler(t *testing.T) {
+	t.Parallel()
+
+	// Sample traces containing any error.
+	decide := func(trace []rb.SpanSummary) bool {
+		return slices.ContainsFunc(trace, func(s rb.SpanSummary) bool { return s.Error })
+	}
+
+	var exported [][]string
+	export := func(trace []rb.SpanSummary) {
+		var ids []string
+		for _, span := range trace {
+			ids = append(ids, span.SpanID)
+		}
+		slices.Sort(ids)
+		exported = append(exported, ids)
+	}
+
+	s := rb.NewTailSampler(10, decide, export)
+
+	// Trace 1 has no errors.
+	s.OnEnd(rb.SpanSummary{TraceID: "1", SpanID: "b", ParentSpanID: "a", Service: "db", Operation: "query"})
+	s.OnEnd(rb.SpanSummary{TraceID: "1", SpanID: "a", Service: "api", Operation: "GET"})
+
+	// Trace 2 has an error in a child span.
+	s.OnEnd(rb.SpanSummary{TraceID: "2", SpanID: "d", ParentSpanID: "c", Service: "db", Operation: "query", Error: true})
+	s.OnEnd(rb.SpanSummary{TraceID: "2", SpanID: "e", ParentSpanID: "c", Service: "cache", Operation: "get"})
+	s.OnEnd(rb.SpanSummary{TraceID: "2", SpanID: "c", Service: "api", Operation: "GET"})
+
+	assertEqual(t, [][]string{{"c", "d", "e"}}, exported)
+
+	// Everything remains buffered by category.
+	_, categories := s.Spans().Stats()
+	assertEqual(t, 3, len(categories))
+	assertEqual(t, 2, categories["db/query"].Count)
+	assertEqual(t, 2, len(s.Trace("1")))
+}
@@ -0,0 +1,109 @@
+package rb
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	_ driver.Valuer = (*RingBuffer[int])(nil)
+	_ sql.Scanner   = (*RingBuffer[int])(nil)
+)
+
+// SetSQLCodec sets the codec used by Value and Scan. By default, values are
+// encoded as a JSON array, oldest first, which suits a JSON or JSONB column.
+// With a codec, values are encoded as a snapshot, as written by Save, which
+// suits a binary column, e.g. BYTEA or BLOB. Pass nil to restore the default.
+func (rb *RingBuffer[T]) SetSQLCodec(codec Codec[T]) {
+	rb.lock()
+	defer rb.unlock()
+
+	rb.sqlCodec = codec
+}
+
+// Value implements driver.Valuer, so that a ring buffer can be stored in a
+// database column. See SetSQLCodec.
+func (rb *RingBuffer[T]) Value() (driver.Value, error) {
+	rb.lock()
+	codec := rb.sqlCodec
+	rb.unlock()
+
+	if codec != nil {
+		var buf bytes.Buffer
+		if err := rb.Save(&buf, codec); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	rb.lock()
+	vals := make([]T, 0, rb.len)
+	for _, segment := range rb.segments() {
+		vals = append(vals, segment...)
+	}
+	rb.unlock()
+
+	return json.Marshal(vals)
+}
+
+// Scan implements sql.Scanner, so that a ring buffer can be restored from a
+// database column written via Value. It replaces the values in the ring buffer
+// with the scanned values, and keeps its capacity, so if there are more scanned
+// values than the capacity, only the newest are kept. A NULL column clears the
+// ring buffer.
+//
+// Snapshots, as written by Save, are decoded with the codec set by SetSQLCodec,
+// or JSONCodec if none is set. Anything else is decoded as a JSON array.
+func (rb *RingBuffer[T]) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("can't scan %T into a ring buffer", src)
+	}
+
+	rb.lock()
+	codec := rb.sqlCodec
+	rb.unlock()
+	if codec == nil {
+		codec = JSONCodec[T]{}
+	}
+
+	var vals []T
+	switch {
+	case data == nil:
+	case bytes.HasPrefix(data, snapshotMagic):
+		var err error
+		if vals, err = ReadSnapshot(bytes.NewReader(data), codec); err != nil {
+			return err
+		}
+	default:
+		if err := json.Unmarshal(data, &vals); err != nil {
+			return fmt.Errorf("decode values: %w", err)
+		}
+	}
+
+	rb.lock()
+	defer rb.unlock()
+
+	// Waiters are notified once, rather than for every value.
+	seq, n := rb.seq, rb.len
+	for rb.len > 0 {
+		rb.dropOldest()
+	}
+	for _, val := range vals {
+		rb.put(val)
+	}
+	if n > 0 || rb.seq != seq {
+		rb.changed()
+	}
+
+	return nil
+}
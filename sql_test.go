@@ -0,0 +1,61 @@
+package rb_test
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferSQL(t *testing.T) {
+	t.Parallel()
+
+	src := rb.NewRingBuffer[int](3)
+	for i := 1; i <= 4; i++ {
+		src.Add(i)
+	}
+
+	// By default, values are a JSON array, oldest first.
+	v, err := src.Value()
+	assertEqual(t, error(nil), err)
+	assertEqual(t, driver.Value([]byte(`[2,3,4]`)), v)
+
+	// Scan replaces values, and keeps the newest if there are too many, in a
+	// single change.
+	dst := rb.NewRingBuffer[int](2)
+	dst.Add(99)
+	gen := dst.Generation()
+	assertEqual(t, error(nil), dst.Scan(v))
+	assertEqual(t, []int{4, 3}, take(dst))
+	assertEqual(t, gen+1, dst.Generation())
+
+	// Some drivers return JSON columns as strings.
+	assertEqual(t, error(nil), dst.Scan(`[5,6]`))
+	assertEqual(t, []int{6, 5}, take(dst))
+
+	// With a codec, values are a snapshot.
+	src.SetSQLCodec(rb.GobCodec[int]{})
+	v, err = src.Value()
+	assertEqual(t, error(nil), err)
+	assertEqual(t, true, bytes.HasPrefix(v.([]byte), []byte("rbsnap")))
+
+	dst.SetSQLCodec(rb.GobCodec[int]{})
+	assertEqual(t, error(nil), dst.Scan(v))
+	assertEqual(t, []int{4, 3}, take(dst))
+
+	// NULL clears the ring buffer.
+	assertEqual(t, error(nil), dst.Scan(nil))
+	assertEqual(t, []int{}, take(dst))
+
+	// Invalid data is an error, and leaves the ring buffer unchanged.
+	dst.Add(7)
+	assertEqual(t, true, dst.Scan([]byte(`{`)) != nil)
+	assertEqual(t, true, dst.Scan(42) != nil)
+	assertEqual(t, []int{7}, take(dst))
+}
+
+func take(b *rb.RingBuffer[int]) []int {
+	vals, _ := b.Take(b.Stats().Capacity)
+	return vals
+}
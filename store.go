@@ -0,0 +1,214 @@
+package rb
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ListStore is a minimal driver for an external store of lists of bytes, with
+// the semantics of the Redis commands of the same names. Indexes are zero-based,
+// and negative indexes count from the end of the list, so -1 is the last value.
+// Implementations must be safe for concurrent use.
+type ListStore interface {
+	// LPush prepends the value to the list at key, creating it if necessary,
+	// and returns the new length of the list.
+	LPush(ctx context.Context, key string, val []byte) (int, error)
+
+	// LRange returns the values from start to stop, inclusive.
+	LRange(ctx context.Context, key string, start, stop int) ([][]byte, error)
+
+	// LTrim trims the list to the values from start to stop, inclusive.
+	LTrim(ctx context.Context, key string, start, stop int) error
+}
+
+// StoreBuffer is a Buffer backed by a list in an external store, which can be
+// shared by many processes. Values are encoded by a codec, and the newest value
+// is at the head of the list, so Add is an LPush followed by an LTrim, and Take
+// is an LRange. Application code written against Buffer can switch between
+// in-memory and shared ring buffers without changes.
+//
+// Each operation is a separate round trip, with a timeout, so if the list is
+// shared by concurrent writers, then the values returned as dropped are best
+// effort. Add, Overview, and Resize can't return errors, so their errors are
+// available via Err.
+type StoreBuffer[T any] struct {
+	store   ListStore
+	key     string
+	sz      int
+	codec   Codec[T]
+	timeout time.Duration
+
+	mtx sync.Mutex
+	err error
+}
+
+var _ Buffer[int] = (*StoreBuffer[int])(nil)
+
+// NewStoreBuffer returns a buffer of size sz, backed by the list at key in the
+// given store. Each operation on the store uses the given timeout, or 1s if
+// it's zero.
+func NewStoreBuffer[T any](store ListStore, key string, sz int, codec Codec[T], timeout time.Duration) *StoreBuffer[T] {
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	return &StoreBuffer[T]{
+		store:   store,
+		key:     key,
+		sz:      max(1, sz),
+		codec:   codec,
+		timeout: timeout,
+	}
+}
+
+// Err returns the most recent error from Add, Overview, or Resize, if any.
+func (b *StoreBuffer[T]) Err() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.err
+}
+
+// Add implements Buffer.
+func (b *StoreBuffer[T]) Add(val T) (dropped T, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	data, err := b.codec.Encode(val)
+	if err != nil {
+		b.setErr(err)
+		return dropped, false
+	}
+
+	n, err := b.store.LPush(ctx, b.key, data)
+	if err != nil {
+		b.setErr(err)
+		return dropped, false
+	}
+
+	sz := b.size()
+	if n <= sz {
+		return dropped, false
+	}
+
+	// Capture the newest of the values about to be trimmed, as with Add on a
+	// RingBuffer, which returns at most one dropped value.
+	trimmed, err := b.store.LRange(ctx, b.key, sz, sz)
+	if err == nil && len(trimmed) > 0 {
+		dropped, err = b.codec.Decode(trimmed[0])
+		ok = err == nil
+	}
+	if err != nil {
+		b.setErr(err)
+	}
+
+	if err := b.store.LTrim(ctx, b.key, 0, sz-1); err != nil {
+		b.setErr(err)
+	}
+
+	return dropped, ok
+}
+
+// Walk implements Buffer. The values are read with a single LRange, and then
+// passed to fn.
+func (b *StoreBuffer[T]) Walk(fn func(T) error) error {
+	vals, err := b.Take(b.size())
+	if err != nil {
+		return err
+	}
+
+	for _, val := range vals {
+		if err := fn(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Take implements Buffer.
+func (b *StoreBuffer[T]) Take(n int) ([]T, error) {
+	n = min(n, b.size())
+	if n <= 0 {
+		return []T{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	datas, err := b.store.LRange(ctx, b.key, 0, n-1)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.decode(datas)
+}
+
+// Overview implements Buffer. The count is computed from a single LRange of
+// the whole list, so it's relatively expensive.
+func (b *StoreBuffer[T]) Overview() (newest, oldest T, count int) {
+	vals, err := b.Take(b.size())
+	if err != nil {
+		b.setErr(err)
+		return newest, oldest, 0
+	}
+	if len(vals) == 0 {
+		return newest, oldest, 0
+	}
+	return vals[0], vals[len(vals)-1], len(vals)
+}
+
+// Resize implements Buffer. The size is local to this StoreBuffer, so other
+// processes sharing the list should be resized, too.
+func (b *StoreBuffer[T]) Resize(sz int) (dropped []T) {
+	if sz <= 0 {
+		return nil
+	}
+
+	b.mtx.Lock()
+	b.sz = sz
+	b.mtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	datas, err := b.store.LRange(ctx, b.key, sz, -1)
+	if err != nil {
+		b.setErr(err)
+		return nil
+	}
+	if err := b.store.LTrim(ctx, b.key, 0, sz-1); err != nil {
+		b.setErr(err)
+		return nil
+	}
+
+	dropped, err = b.decode(datas)
+	if err != nil {
+		b.setErr(err)
+	}
+	return dropped
+}
+
+func (b *StoreBuffer[T]) size() int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.sz
+}
+
+func (b *StoreBuffer[T]) setErr(err error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.err = err
+}
+
+func (b *StoreBuffer[T]) decode(datas [][]byte) ([]T, error) {
+	vals := make([]T, len(datas))
+	for i, data := range datas {
+		var err error
+		if vals[i], err = b.codec.Decode(data); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
@@ -0,0 +1,38 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestStoreBuffer(t *testing.T) {
+	t.Parallel()
+
+	store := rbtest.NewListStore()
+	b := rb.NewStoreBuffer[int](store, "k", 3, rb.JSONCodec[int]{}, 0)
+
+	for i := 1; i <= 3; i++ {
+		_, ok := b.Add(i)
+		assertEqual(t, false, ok)
+	}
+	dropped, ok := b.Add(4)
+	assertEqual(t, true, ok)
+	assertEqual(t, 1, dropped)
+
+	vals, err := b.Take(10)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, []int{4, 3, 2}, vals)
+
+	newest, oldest, count := b.Overview()
+	assertEqual(t, [3]int{4, 2, 3}, [3]int{newest, oldest, count})
+
+	// Another buffer over the same key sees the same values.
+	other := rb.NewStoreBuffer[int](store, "k", 3, rb.JSONCodec[int]{}, 0)
+	rbtest.AssertContents[int](t, other, []int{4, 3, 2})
+
+	assertEqual(t, []int{2}, b.Resize(2))
+	rbtest.AssertContents[int](t, b, []int{4, 3})
+	assertEqual(t, error(nil), b.Err())
+}
@@ -0,0 +1,69 @@
+package rb
+
+import (
+	"context"
+)
+
+// Subscribe calls fn for every value in the ring buffer, oldest first, and then
+// continues to call fn for every value subsequently added, as they're added,
+// until the context is done or fn returns an error. Each value is delivered
+// exactly once, and there's no gap between the current values and the values
+// that are added later.
+//
+// Values are delivered without holding the lock. If fn is slow enough that the
+// ring buffer overwrites values before they can be delivered, those values are
+// skipped, and delivery continues from the oldest remaining value.
+func (rb *RingBuffer[T]) Subscribe(ctx context.Context, fn func(T) error) error {
+	return rb.SubscribeEntries(ctx, 0, func(e Entry[T]) error { return fn(e.Value) })
+}
+
+// SubscribeEntries is like Subscribe, but delivers entries, which include the
+// sequence number of each value, and starts with the oldest value whose
+// sequence number is greater than or equal to since. Pass 0 to start with the
+// oldest value, or the sequence number after the last entry seen, to resume.
+// A gap between consecutive sequence numbers means values were skipped.
+//
+// If since is ahead of the ring buffer, e.g. because it was seen in a ring
+// buffer which has since been replaced by a restart, delivery starts with the
+// oldest value, which is visible as a sequence number lower than since.
+func (rb *RingBuffer[T]) SubscribeEntries(ctx context.Context, since uint64, fn func(Entry[T]) error) error {
+	next := since
+
+	for {
+		rb.lock()
+		if next > rb.seq {
+			next = rb.seq - uint64(rb.len)
+		}
+		entries, seq := rb.entriesSince(next)
+		gen := rb.gen
+		rb.unlock()
+
+		for _, e := range entries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+
+		next = max(next, seq)
+
+		if _, err := rb.Wait(ctx, gen); err != nil {
+			return err
+		}
+	}
+}
+
+// entriesSince returns the entries in the ring buffer with sequence numbers
+// greater than or equal to seq, oldest first, as well as the sequence number of
+// the next value to be added. It assumes the lock is held.
+func (rb *RingBuffer[T]) entriesSince(seq uint64) (entries []Entry[T], next uint64) {
+	n := rb.countSince(seq)
+	entries = make([]Entry[T], n)
+	for i := range n {
+		entries[n-1-i] = rb.entry(i)
+	}
+
+	return entries, rb.seq
+}
@@ -0,0 +1,82 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](3)
+	for i := range 5 {
+		rb.Add(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan int)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- rb.Subscribe(ctx, func(i int) error {
+			received <- i
+			return nil
+		})
+	}()
+
+	// First the existing values, oldest first.
+	assertEqual(t, 2, <-received)
+	assertEqual(t, 3, <-received)
+	assertEqual(t, 4, <-received)
+
+	// Then live values, with no gaps or duplicates.
+	for i := 5; i < 100; i++ {
+		rb.Add(i)
+		assertEqual(t, i, <-received)
+	}
+
+	cancel()
+	assertEqual(t, true, errors.Is(<-errc, context.Canceled))
+}
+
+func TestSubscribeError(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](3)
+	rb.Add(1)
+	rb.Add(2)
+
+	errStop := errors.New("stop")
+	var have []int
+	err := rb.Subscribe(context.Background(), func(i int) error {
+		have = append(have, i)
+		return errStop
+	})
+	assertEqual(t, true, errors.Is(err, errStop))
+	assertEqual(t, []int{1}, have)
+}
+
+func TestSubscribeEntriesAhead(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[int](3)
+	buf.Add(1)
+	buf.Add(2)
+
+	// A since from a previous ring buffer starts over with the oldest value.
+	errStop := errors.New("stop")
+	var have []uint64
+	err := buf.SubscribeEntries(context.Background(), 100, func(e rb.Entry[int]) error {
+		have = append(have, e.Seq)
+		if len(have) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	assertEqual(t, true, errors.Is(err, errStop))
+	assertEqual(t, []uint64{0, 1}, have)
+}
@@ -0,0 +1,135 @@
+package rb
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// TailHandler returns an HTTP handler which streams the ring buffer to clients,
+// as newline-delimited JSON entries: first the current values, oldest first,
+// and then every value subsequently added, until the client disconnects. Values
+// are encoded with encoding/json. See Tail for a matching client.
+//
+// Clients can resume from a particular point with the query parameter since,
+// which is the sequence number of the first entry to send, as with
+// SubscribeEntries. A gap in sequence numbers means values were skipped.
+func (rb *RingBuffer[T]) TailHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveTail(w, r, rb)
+	})
+}
+
+// TailHandler returns an HTTP handler which streams a single ring buffer,
+// identified by the query parameter category, as with RingBuffer.TailHandler.
+// Unknown categories return 404 Not Found, rather than being created.
+func (rbs *RingBuffers[T]) TailHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		category := r.URL.Query().Get("category")
+		if category == "" {
+			http.Error(w, "category is required", http.StatusBadRequest)
+			return
+		}
+
+		rb, ok := rbs.GetAll()[category]
+		if !ok {
+			http.Error(w, "category not found", http.StatusNotFound)
+			return
+		}
+
+		serveTail(w, r, rb)
+	})
+}
+
+func serveTail[T any](w http.ResponseWriter, r *http.Request, rb *RingBuffer[T]) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var since uint64
+	if s := r.URL.Query().Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, "invalid since", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	rc.Flush() // send headers now, best effort
+
+	enc := json.NewEncoder(w)
+	rb.SubscribeEntries(r.Context(), since, func(e Entry[T]) error {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+		rc.Flush() // best effort
+		return nil
+	})
+}
+
+// Tail connects to a TailHandler at the given URL, and calls fn for every entry
+// it receives, starting with the entry with sequence number since, or with the
+// oldest entry, if since is ahead of the remote ring buffer. It returns when
+// the context is done, the stream ends, or fn returns an error. It always
+// returns the sequence number to pass as since to resume after the last entry
+// which was successfully delivered to fn. If client is nil, the default client
+// is used.
+func Tail[T any](ctx context.Context, client *http.Client, rawurl string, since uint64, fn func(Entry[T]) error) (next uint64, err error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return since, fmt.Errorf("parse URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("since", strconv.FormatUint(since, 10))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return since, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return since, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return since, fmt.Errorf("%s: %s", resp.Status, body)
+	}
+
+	next = since
+	s := bufio.NewScanner(resp.Body)
+	s.Buffer(nil, 64<<20)
+	for s.Scan() {
+		var e Entry[T]
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return next, fmt.Errorf("decode entry: %w", err)
+		}
+		if err := fn(e); err != nil {
+			return next, err
+		}
+		next = e.Seq + 1
+	}
+
+	if err := ctx.Err(); err != nil {
+		return next, err
+	}
+	return next, s.Err()
+}
@@ -0,0 +1,92 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestTail(t *testing.T) {
+	t.Parallel()
+
+	src := rb.NewRingBuffer[string](3)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		src.Add(s)
+	}
+
+	server := httptest.NewServer(src.TailHandler())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errStop := errors.New("stop")
+	take := func(since uint64, n int) ([]rb.Entry[string], uint64) {
+		t.Helper()
+		var entries []rb.Entry[string]
+		next, err := rb.Tail(ctx, nil, server.URL, since, func(e rb.Entry[string]) error {
+			entries = append(entries, e)
+			if len(entries) >= n {
+				return errStop
+			}
+			return nil
+		})
+		assertEqual(t, true, errors.Is(err, errStop))
+		return entries, next
+	}
+
+	// Initial snapshot, then live updates.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		src.Add("e")
+	}()
+	entries, next := take(0, 4)
+	assertEqual(t, []rb.Entry[string]{{Seq: 1, Value: "b"}, {Seq: 2, Value: "c"}, {Seq: 3, Value: "d"}, {Seq: 4, Value: "e"}}, entries)
+	assertEqual(t, uint64(4), next)
+
+	// Resume by sequence number. The entry for which fn returned an error
+	// wasn't delivered successfully, so it's delivered again.
+	src.Add("f")
+	entries, next = take(next, 2)
+	assertEqual(t, []rb.Entry[string]{{Seq: 4, Value: "e"}, {Seq: 5, Value: "f"}}, entries)
+	assertEqual(t, uint64(5), next)
+}
+
+func TestRingBuffersTailHandler(t *testing.T) {
+	t.Parallel()
+
+	rbs := rb.NewRingBuffers[int](3)
+	rbs.GetOrCreate("foo").Add(1)
+
+	server := httptest.NewServer(rbs.TailHandler())
+	defer server.Close()
+
+	for query, want := range map[string]int{
+		"":                      http.StatusBadRequest,
+		"?category=bar":         http.StatusNotFound,
+		"?category=foo&since=x": http.StatusBadRequest,
+	} {
+		resp, err := http.Get(server.URL + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		assertEqual(t, want, resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var have []int
+	_, err := rb.Tail(ctx, nil, server.URL+"?category=foo", 0, func(e rb.Entry[int]) error {
+		have = append(have, e.Value)
+		return errors.New("stop")
+	})
+	assertEqual(t, true, strings.Contains(err.Error(), "stop"))
+	assertEqual(t, []int{1}, have)
+}
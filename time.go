@@ -0,0 +1,114 @@
+package rb
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+// EnableTimestamps records the time each value is added to the ring buffer,
+// using the given clock, or time.Now if it's nil. Values already in the ring
+// buffer have a zero timestamp. Timestamps enable time-based methods, such as
+// Staleness. The clock is also used for TTL expiry, see AddTTL.
+func (rb *RingBuffer[T]) EnableTimestamps(now func() time.Time) {
+	rb.lock()
+	defer rb.unlock()
+
+	if now == nil {
+		now = time.Now
+	}
+
+	rb.trackMeta()
+	rb.now = now
+	rb.setHooks()
+}
+
+// Staleness returns the time since the newest value was added to the ring
+// buffer, which is useful for detecting when producers have stopped adding
+// values. If the ring buffer is empty, or timestamps aren't enabled, it returns
+// the maximum possible duration.
+func (rb *RingBuffer[T]) Staleness() time.Duration {
+	rb.lock()
+	defer rb.unlock()
+
+	if rb.now == nil || rb.len == 0 {
+		return math.MaxInt64
+	}
+
+	return rb.now().Sub(time.Unix(0, rb.meta[rb.index(0)].time))
+}
+
+// Stale returns true if the Staleness of the ring buffer exceeds threshold.
+func (rb *RingBuffer[T]) Stale(threshold time.Duration) bool {
+	return rb.Staleness() > threshold
+}
+
+// clock returns the current time, according to the ring buffer's clock. It
+// assumes the lock is held.
+func (rb *RingBuffer[T]) clock() time.Time {
+	if rb.now != nil {
+		return rb.now()
+	}
+	return time.Now()
+}
+
+// CountSince returns the number of values added to the ring buffer at or after
+// t. It walks from the newest value, and stops at the first value older than t,
+// so it's proportional to the number of counted values, not the size of the
+// ring buffer. If timestamps aren't enabled, it returns 0.
+func (rb *RingBuffer[T]) CountSince(t time.Time) int {
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.countSinceTime(t.UnixNano())
+}
+
+// countSinceTime returns the number of the newest values with timestamps at or
+// after the cutoff. It assumes the lock is held.
+func (rb *RingBuffer[T]) countSinceTime(cutoff int64) int {
+	if rb.now == nil {
+		return 0
+	}
+
+	var n int
+	for n < rb.len && rb.meta[rb.index(n)].time >= cutoff {
+		n += 1
+	}
+	return n
+}
+
+// searchTime returns the number of the newest values with timestamps at or
+// after the cutoff, like countSinceTime, but by binary search, so it's O(log n)
+// rather than proportional to the result. It assumes the lock is held, and
+// that timestamps are enabled.
+func (rb *RingBuffer[T]) searchTime(cutoff int64) int {
+	return sort.Search(rb.len, func(i int) bool { return rb.meta[rb.index(i)].time < cutoff })
+}
+
+// Between returns the values added to the ring buffer at or after from, and
+// before to, oldest first. The newest value in the range is found by binary
+// search, and the walk stops at the first value older than from, so it's
+// proportional to the number of returned values, rather than the size of the
+// ring buffer. It assumes the clock is monotonic. If timestamps aren't enabled,
+// it returns no values.
+func (rb *RingBuffer[T]) Between(from, to time.Time) []T {
+	rb.lock()
+	defer rb.unlock()
+
+	if rb.now == nil {
+		return []T{}
+	}
+
+	// Values are ordered by time, newest first, so the range is contiguous.
+	newest := rb.searchTime(to.UnixNano())
+
+	vals := []T{}
+	from64 := from.UnixNano()
+	for i := newest; i < rb.len && rb.meta[rb.index(i)].time >= from64; i++ {
+		vals = append(vals, rb.buf[rb.index(i)])
+	}
+
+	slices.Reverse(vals)
+	return vals
+}
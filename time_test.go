@@ -0,0 +1,103 @@
+package rb_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+// epoch is the initial time of clocks in tests.
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestStaleness(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	rb := rb.NewRingBuffer[int](3)
+
+	// Without timestamps, everything is stale.
+	rb.Add(1)
+	assertEqual(t, time.Duration(math.MaxInt64), rb.Staleness())
+
+	rb.EnableTimestamps(clock.Now)
+	rb.Clear()
+	assertEqual(t, time.Duration(math.MaxInt64), rb.Staleness())
+	assertEqual(t, true, rb.Stale(time.Hour))
+
+	rb.Add(2)
+	clock.Advance(time.Second)
+	assertEqual(t, time.Second, rb.Staleness())
+	assertEqual(t, false, rb.Stale(time.Minute))
+
+	clock.Advance(time.Minute)
+	assertEqual(t, true, rb.Stale(time.Minute))
+
+	rb.Add(3)
+	assertEqual(t, time.Duration(0), rb.Staleness())
+}
+
+func TestTimestampsTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	rb := rb.NewRingBuffer[int](3)
+	rb.EnableTimestamps(clock.Now)
+
+	rb.AddTTL(1, time.Minute)
+	rb.AddTTL(2, time.Hour)
+
+	clock.Advance(59 * time.Second)
+	vals, _ := rb.Take(10)
+	assertEqual(t, []int{2, 1}, vals)
+
+	clock.Advance(time.Second)
+	vals, _ = rb.Take(10)
+	assertEqual(t, []int{2}, vals)
+}
+
+func TestCountSince(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	start := clock.Now()
+
+	errs := rb.NewRingBuffer[int](100)
+	assertEqual(t, 0, errs.CountSince(start)) // no timestamps
+	errs.EnableTimestamps(clock.Now)
+
+	for i := 1; i <= 10; i++ {
+		errs.Add(i)
+		clock.Advance(10 * time.Second)
+	}
+
+	// Values were added at 0s, 10s, ..., 90s, and now is 100s.
+	assertEqual(t, 10, errs.CountSince(start))
+	assertEqual(t, 6, errs.CountSince(clock.Now().Add(-time.Minute)))
+	assertEqual(t, 0, errs.CountSince(clock.Now()))
+}
+
+func TestBetween(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	start := clock.Now()
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	buf := rb.NewRingBuffer[int](10)
+	assertEqual(t, []int{}, buf.Between(at(0), at(10)))
+
+	buf.EnableTimestamps(clock.Now)
+	for i := range 8 {
+		buf.Add(i) // added at minute i
+		clock.Advance(time.Minute)
+	}
+
+	assertEqual(t, []int{2, 3, 4}, buf.Between(at(2), at(5)))
+	assertEqual(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, buf.Between(at(-1), at(100)))
+	assertEqual(t, []int{7}, buf.Between(at(7), at(8)))
+	assertEqual(t, []int{}, buf.Between(at(20), at(30)))
+	assertEqual(t, []int{}, buf.Between(at(5), at(2)))
+}
@@ -0,0 +1,158 @@
+package rb
+
+import (
+	"context"
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// Trimmer is implemented by ring buffers which can release memory on demand,
+// by dropping their oldest values. RingBuffer and RingBuffers implement it.
+type Trimmer interface {
+	TrimOldest(fraction float64) int
+}
+
+var (
+	_ Trimmer = (*RingBuffer[int])(nil)
+	_ Trimmer = (*RingBuffers[int])(nil)
+)
+
+// TrimOldest drops the given fraction of the values in the ring buffer, oldest
+// first, rounded up, and returns the number of values dropped. The slots of
+// dropped values are cleared, so values of pointer types can be garbage
+// collected, and in the read-optimized mode, see PublishEvery, a snapshot
+// without them is published immediately, so it doesn't retain them either.
+// Pinned values aren't preserved. The fraction is clamped to [0, 1].
+func (rb *RingBuffer[T]) TrimOldest(fraction float64) int {
+	rb.lock()
+	defer rb.unlock()
+
+	n := int(math.Ceil(float64(rb.len) * min(max(0, fraction), 1)))
+	if n == 0 {
+		return 0
+	}
+
+	for range n {
+		rb.dropOldest()
+	}
+
+	rb.changed()
+	if rb.publishEvery > 0 && rb.unpublished > 0 {
+		rb.publish()
+	}
+
+	return n
+}
+
+// TrimOldest calls TrimOldest on every ring buffer, and returns the total
+// number of values dropped.
+func (rbs *RingBuffers[T]) TrimOldest(fraction float64) int {
+	var n int
+	for _, rb := range rbs.GetAll() {
+		n += rb.TrimOldest(fraction)
+	}
+	return n
+}
+
+// MemoryTrimmer trims ring buffers when the memory used by the Go runtime
+// approaches the memory limit, so that a flight recorder yields its memory
+// before the application runs out. The memory limit is the soft limit set via
+// debug.SetMemoryLimit or GOMEMLIMIT, so there's nothing to trim unless it's set,
+// or Limit is set explicitly.
+type MemoryTrimmer struct {
+	// Limit is the memory limit, in bytes. If it's zero, the runtime's soft
+	// memory limit is used.
+	Limit int64
+
+	// Threshold is the fraction of the limit at which ring buffers are trimmed.
+	// If it's zero, 0.9 is used.
+	Threshold float64
+
+	// Fraction is the fraction of values trimmed from each ring buffer, each
+	// time the threshold is exceeded. If it's zero, 0.25 is used.
+	Fraction float64
+
+	// Interval is how often memory use is checked by Run. If it's zero, 1s is
+	// used.
+	Interval time.Duration
+
+	// OnTrim, if non-nil, is called with the memory used, and the number of
+	// values dropped, each time ring buffers are trimmed.
+	OnTrim func(used uint64, dropped int)
+
+	trimmers []Trimmer
+}
+
+// NewMemoryTrimmer returns a memory trimmer for the given ring buffers.
+// Exported fields should be set before calling Run or Check.
+func NewMemoryTrimmer(trimmers ...Trimmer) *MemoryTrimmer {
+	return &MemoryTrimmer{trimmers: trimmers}
+}
+
+// Run checks memory use at every interval, until the context is done.
+func (m *MemoryTrimmer) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			m.Check()
+		}
+	}
+}
+
+// Check trims the ring buffers once, if memory use exceeds the threshold, and
+// returns the number of values dropped.
+func (m *MemoryTrimmer) Check() int {
+	limit := m.Limit
+	if limit <= 0 {
+		limit = debug.SetMemoryLimit(-1)
+	}
+	if limit <= 0 || limit == math.MaxInt64 {
+		return 0
+	}
+
+	threshold := m.Threshold
+	if threshold <= 0 {
+		threshold = 0.9
+	}
+	fraction := m.Fraction
+	if fraction <= 0 {
+		fraction = 0.25
+	}
+
+	used := memoryUsed()
+	if float64(used) < threshold*float64(limit) {
+		return 0
+	}
+
+	var dropped int
+	for _, t := range m.trimmers {
+		dropped += t.TrimOldest(fraction)
+	}
+	if m.OnTrim != nil {
+		m.OnTrim(used, dropped)
+	}
+	return dropped
+}
+
+// memoryUsed returns the memory used by the Go runtime, as counted against the
+// memory limit, i.e. all mapped memory, minus heap memory returned to the OS.
+func memoryUsed() uint64 {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	return samples[0].Value.Uint64() - samples[1].Value.Uint64()
+}
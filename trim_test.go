@@ -0,0 +1,80 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferTrimOldest(t *testing.T) {
+	t.Parallel()
+
+	buf := rb.NewRingBuffer[*int](10)
+	for i := range 10 {
+		buf.Add(&i)
+	}
+
+	assertEqual(t, 3, buf.TrimOldest(0.25)) // rounded up
+	newest, oldest, count := buf.Overview()
+	assertEqual(t, 9, *newest)
+	assertEqual(t, 3, *oldest)
+	assertEqual(t, 7, count)
+
+	assertEqual(t, 0, buf.TrimOldest(0))
+	assertEqual(t, 7, buf.TrimOldest(2))
+	_, _, count = buf.Overview()
+	assertEqual(t, 0, count)
+}
+
+func TestRingBufferTrimOldestPublished(t *testing.T) {
+	t.Parallel()
+
+	var lows int
+	buf := rb.NewRingBuffer[int](10)
+	for i := range 10 {
+		buf.Add(i)
+	}
+	buf.PublishEvery(5)
+	buf.OnLowWater(0.5, func() { lows++ })
+
+	// A trim is a single change, which is published immediately, so the
+	// snapshot doesn't retain the dropped values.
+	gen := buf.Generation()
+	assertEqual(t, 8, buf.TrimOldest(0.8))
+	assertEqual(t, gen+1, buf.Generation())
+	assertEqual(t, []int{9, 8}, buf.Published())
+	assertEqual(t, 1, lows)
+
+	buf.PublishEvery(1)
+	assertEqual(t, 2, buf.TrimOldest(1))
+	assertEqual(t, gen+2, buf.Generation())
+	assertEqual(t, []int{}, buf.Published())
+}
+
+func TestMemoryTrimmer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a   = rb.NewRingBuffer[int](10)
+		rbs = rb.NewRingBuffers[int](10)
+	)
+	for i := range 10 {
+		a.Add(i)
+		rbs.GetOrCreate("b").Add(i)
+	}
+
+	var trimmed int
+	m := rb.NewMemoryTrimmer(a, rbs)
+	m.Limit = 1 << 50 // far more than is used
+	m.OnTrim = func(_ uint64, dropped int) { trimmed += dropped }
+	assertEqual(t, 0, m.Check())
+
+	m.Limit = 1 // far less than is used
+	m.Fraction = 0.5
+	assertEqual(t, 10, m.Check())
+	assertEqual(t, 10, trimmed)
+	_, _, count := a.Overview()
+	assertEqual(t, 5, count)
+	_, _, count = rbs.GetOrCreate("b").Overview()
+	assertEqual(t, 5, count)
+}
@@ -0,0 +1,84 @@
+package rb
+
+import (
+	"time"
+)
+
+// AddTTL is like Add, but the value expires after the given TTL, independently
+// of other values. Expired values are removed lazily, the next time the ring
+// buffer is accessed, and are never observed by readers. A TTL <= 0 means the
+// value never expires, the same as Add. Values added with AddTTL require extra
+// bookkeeping, and make most operations slightly more expensive.
+func (rb *RingBuffer[T]) AddTTL(val T, ttl time.Duration) (dropped T, ok bool) {
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.addTTL(val, ttl)
+}
+
+// addTTL is AddTTL, and assumes the lock is held.
+func (rb *RingBuffer[T]) addTTL(val T, ttl time.Duration) (dropped T, ok bool) {
+	if ttl <= 0 {
+		return rb.add(val)
+	}
+
+	rb.trackMeta()
+
+	dropped, ok = rb.add(val)
+
+	// The add may have been rejected by an eviction policy.
+	if rb.len > 0 && rb.seqAt(0) == rb.seq-1 {
+		rb.setExpiry(rb.index(0), rb.clock().Add(ttl).UnixNano())
+	}
+
+	return dropped, ok
+}
+
+// SetTTL sets a default TTL for every value subsequently added to the ring
+// buffer, which bounds the retention of values by time, as well as by count.
+// AddTTL with a TTL > 0 overrides the default. Values already in the ring
+// buffer are unaffected. A TTL <= 0 removes the default.
+func (rb *RingBuffer[T]) SetTTL(ttl time.Duration) {
+	rb.lock()
+	defer rb.unlock()
+
+	if ttl > 0 {
+		rb.trackMeta()
+	}
+	rb.ttl = max(0, ttl)
+}
+
+// setExpiry sets the expiry of the value at the given index in buf. It assumes
+// the lock is held, and that metadata is tracked.
+func (rb *RingBuffer[T]) setExpiry(index int, expires int64) {
+	rb.meta[index].expires = expires
+	if rb.nextExpiry == 0 || expires < rb.nextExpiry {
+		rb.nextExpiry = expires
+	}
+}
+
+// expire removes all values which have expired as of now. It assumes the lock
+// is held.
+func (rb *RingBuffer[T]) expire(now time.Time) {
+	if rb.nextExpiry == 0 || now.UnixNano() < rb.nextExpiry {
+		return
+	}
+
+	cutoff := now.UnixNano()
+	removed := rb.compact(func(index int) bool {
+		expires := rb.meta[index].expires
+		return expires == 0 || expires > cutoff
+	})
+
+	rb.nextExpiry = 0
+	for i := range rb.len {
+		if expires := rb.meta[rb.index(i)].expires; expires != 0 && (rb.nextExpiry == 0 || expires < rb.nextExpiry) {
+			rb.nextExpiry = expires
+		}
+	}
+
+	if removed > 0 {
+		rb.recost()
+		rb.changed()
+	}
+}
@@ -0,0 +1,64 @@
+package rb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestAddTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	rb := rb.NewRingBuffer[string](5)
+	rb.EnableTimestamps(clock.Now)
+	cp := rb.Mark()
+
+	rb.Add("audit 1")
+	rb.AddTTL("debug 1", time.Second)
+	rb.AddTTL("audit 2", time.Hour)
+	rb.AddTTL("debug 2", time.Second)
+	rb.Add("audit 3")
+
+	clock.Advance(time.Minute)
+
+	vals, _ := rb.Take(10)
+	assertEqual(t, []string{"audit 3", "audit 2", "audit 1"}, vals)
+
+	// Expired values free up capacity.
+	rb.Add("audit 4")
+	rb.Add("audit 5")
+	vals, _ = rb.Take(10)
+	assertEqual(t, []string{"audit 5", "audit 4", "audit 3", "audit 2", "audit 1"}, vals)
+
+	// And are visible as a gap.
+	vals, ok := rb.SinceCheckpoint(cp)
+	assertEqual(t, 5, len(vals))
+	assertEqual(t, false, ok)
+}
+
+func TestSetTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	buf := rb.NewRingBuffer[int](5)
+	buf.EnableTimestamps(clock.Now)
+	buf.SetTTL(time.Minute)
+
+	buf.Add(1)
+	clock.Advance(30 * time.Second)
+	buf.Add(2)
+	buf.AddTTL(3, time.Hour) // overrides the default
+	clock.Advance(45 * time.Second)
+
+	vals, _ := buf.Take(10)
+	assertEqual(t, []int{3, 2}, vals)
+
+	buf.SetTTL(0)
+	buf.Add(4)
+	clock.Advance(time.Hour)
+	vals, _ = buf.Take(10)
+	assertEqual(t, []int{4}, vals)
+}
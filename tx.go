@@ -0,0 +1,94 @@
+package rb
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// txBuffer is implemented by types which can participate in a transaction.
+// It's unexported, as Tx takes ring buffers of different types, so only types
+// in this package implement it.
+type txBuffer interface {
+	txMutex() *sync.Mutex // identifies the buffer, and orders locking
+	lock()
+	release() (fired []func())
+	hold(reason uint32)
+	unhold()
+}
+
+// Transaction updates multiple ring buffers atomically with respect to readers.
+// Transactions aren't safe for concurrent use by multiple goroutines.
+type Transaction struct {
+	bufs   []txBuffer // deduplicated, in stable lock order
+	active bool       // true only during Do
+}
+
+// Tx returns a transaction over the given ring buffers. Use it to add values to
+// several buffers such that readers will observe either all of the adds, or
+// none of them.
+//
+//	tx := rb.Tx(requests, responses)
+//	tx.Do(func() {
+//		requests.AddTx(tx, req)
+//		responses.AddTx(tx, res)
+//	})
+func Tx(bufs ...txBuffer) *Transaction {
+	bufs = slices.Clone(bufs)
+
+	// Locks are always acquired in order of address, so that concurrent
+	// transactions over overlapping sets of buffers can't deadlock.
+	slices.SortFunc(bufs, func(a, b txBuffer) int {
+		return cmp.Compare(uintptr(unsafe.Pointer(a.txMutex())), uintptr(unsafe.Pointer(b.txMutex())))
+	})
+
+	return &Transaction{
+		bufs: slices.CompactFunc(bufs, func(a, b txBuffer) bool { return a.txMutex() == b.txMutex() }),
+	}
+}
+
+// Do locks every ring buffer in the transaction, calls fn, and then unlocks
+// them. The ring buffers are locked in the same way as by their own methods,
+// so e.g. expired values are removed first. Within fn, values should be added
+// with AddTx; calling any other method on a ring buffer in the transaction
+// would deadlock, so it panics instead, unless other goroutines are in walks or
+// transactions at the same time, in which case it can't be detected, see Walk.
+// Watermark callbacks are called once every ring buffer is unlocked.
+func (tx *Transaction) Do(fn func()) {
+	for _, buf := range tx.bufs {
+		buf.lock()
+	}
+
+	tx.active = true
+
+	defer func() {
+		tx.active = false
+		var fired []func()
+		for i := len(tx.bufs) - 1; i >= 0; i-- {
+			fired = append(fired, tx.bufs[i].release()...)
+		}
+		for _, fn := range fired {
+			fn()
+		}
+	}()
+
+	callbacks.Add(1)
+	defer callbacks.Add(-1)
+
+	for _, buf := range tx.bufs {
+		buf.hold(heldTx)
+	}
+	defer func() {
+		for _, buf := range tx.bufs {
+			buf.unhold()
+		}
+	}()
+
+	callbackFrame(fn)
+}
+
+// holds returns true if the transaction is active and holds the given lock.
+func (tx *Transaction) holds(mtx *sync.Mutex) bool {
+	return tx.active && slices.ContainsFunc(tx.bufs, func(buf txBuffer) bool { return buf.txMutex() == mtx })
+}
@@ -0,0 +1,149 @@
+package rb_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/rb"
+	"github.com/peterbourgon/rb/rbtest"
+)
+
+func TestTx(t *testing.T) {
+	t.Parallel()
+
+	requests := rb.NewRingBuffer[int](10)
+	responses := rb.NewRingBuffer[int](10)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// Readers should never observe one buffer updated without the other.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			var a, b []int
+			tx := rb.Tx(requests, responses)
+			tx.Do(func() {
+				a, _ = requests.TakeTx(tx, 10)
+				b, _ = responses.TakeTx(tx, 10)
+			})
+			for i := range max(len(a), len(b)) {
+				if i >= len(a) || i >= len(b) || a[i] != -b[i] {
+					t.Errorf("torn read: requests=%v responses=%v", a, b)
+					return
+				}
+			}
+
+			// Each response cancels out its request.
+			var sum int
+			rb.WalkMerged(func(a, b int) bool { return a < b }, func(v int) error { sum += v; return nil }, requests, responses)
+			if sum != 0 {
+				t.Errorf("torn merged read: sum=%d", sum)
+				return
+			}
+		}
+	}()
+
+	for i := range 1000 {
+		tx := rb.Tx(responses, requests, responses)
+		tx.Do(func() {
+			requests.AddTx(tx, i)
+			responses.AddTx(tx, -i)
+		})
+	}
+
+	have, _ := requests.Take(3)
+	assertEqual(t, []int{999, 998, 997}, have)
+	have, _ = responses.Take(3)
+	assertEqual(t, []int{-999, -998, -997}, have)
+}
+
+func TestTxFeatures(t *testing.T) {
+	t.Parallel()
+
+	clock := rbtest.NewClock(epoch)
+	a := rb.NewRingBuffer[int](5)
+	a.EnableTimestamps(clock.Now)
+	a.AddTTL(1, time.Minute)
+	b := rb.NewRingBuffer[int](5)
+	b.SetSampleRate(0.5)
+
+	var ops []rb.Operation
+	a.Instrument(func(op rb.Operation) { ops = append(ops, op) })
+
+	// Transactions lock like other methods, so expired values are removed.
+	clock.Advance(time.Hour)
+	var vals []int
+	tx := rb.Tx(a, b)
+	tx.Do(func() {
+		vals, _ = a.TakeTx(tx, 5)
+		a.AddTx(tx, 2)
+		for i := range 1000 {
+			b.AddTx(tx, i)
+		}
+	})
+	assertEqual(t, []int{}, vals)
+	vals, _ = a.Take(5)
+	assertEqual(t, []int{2}, vals)
+
+	// AddTx is instrumented, and sampled.
+	assertEqual(t, 1, len(ops))
+	assertEqual(t, "Add", ops[0].Name)
+	added := b.Stats().Added
+	assertEqual(t, true, added > 300 && added < 700)
+}
+
+func TestTxOutsideDo(t *testing.T) {
+	t.Parallel()
+
+	a := rb.NewRingBuffer[int](1)
+	b := rb.NewRingBuffer[int](1)
+	tx := rb.Tx(a)
+
+	assertPanics(t, func() { a.AddTx(tx, 1) })
+	tx.Do(func() { assertPanics(t, func() { b.AddTx(tx, 1) }) })
+}
+
+func TestTxReentrant(t *testing.T) {
+	// Not parallel: reentrant calls are only detected when no other goroutine
+	// is in a walk or transaction at the same time.
+
+	a := rb.NewRingBuffer[int](3)
+	b := rb.NewRingBuffer[int](3)
+	tx := rb.Tx(a, b)
+
+	// Other methods on ring buffers in the transaction panic, rather than
+	// deadlock, and the transaction still completes.
+	tx.Do(func() {
+		a.AddTx(tx, 1)
+		assertPanics(t, func() { a.Add(2) })
+		assertPanics(t, func() { b.Take(1) })
+		var perr *rb.PanicError
+		assertEqual(t, true, errors.As(a.Walk(func(int) error { return nil }), &perr))
+	})
+	vals, _ := a.Take(3)
+	assertEqual(t, []int{1}, vals)
+}
+
+func assertPanics(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	fn()
+}
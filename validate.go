@@ -0,0 +1,85 @@
+package rb
+
+import (
+	"fmt"
+)
+
+// Validate checks the internal invariants of the ring buffer, and returns an
+// error describing the first violation it finds, if any. A non-nil error means
+// the ring buffer is corrupt, which indicates a bug.
+//
+// When built with the rbdebug build tag, every mutation is validated, and a
+// violation causes a panic. That's useful when fuzzing, but expensive.
+func (rb *RingBuffer[T]) Validate() error {
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.validate()
+}
+
+// validate assumes the lock is held.
+func (rb *RingBuffer[T]) validate() error {
+	if rb.len < 0 || rb.len > len(rb.buf) {
+		return fmt.Errorf("len %d out of bounds for capacity %d", rb.len, len(rb.buf))
+	}
+
+	if (len(rb.buf) == 0 && rb.cur != 0) || (len(rb.buf) > 0 && (rb.cur < 0 || rb.cur >= len(rb.buf))) {
+		return fmt.Errorf("cursor %d out of bounds for capacity %d", rb.cur, len(rb.buf))
+	}
+
+	if rb.mask != maskFor(len(rb.buf)) {
+		return fmt.Errorf("mask %d doesn't match capacity %d", rb.mask, len(rb.buf))
+	}
+
+	if uint64(rb.len) > rb.seq {
+		return fmt.Errorf("len %d greater than number of values ever added %d", rb.len, rb.seq)
+	}
+
+	if rb.meta != nil {
+		if len(rb.meta) != len(rb.buf) {
+			return fmt.Errorf("metadata length %d doesn't match capacity %d", len(rb.meta), len(rb.buf))
+		}
+		for i := range rb.len {
+			prev := rb.seq
+			if i > 0 {
+				prev = rb.seqAt(i - 1)
+			}
+			if seq := rb.seqAt(i); seq >= prev {
+				return fmt.Errorf("value %d has sequence number %d, want less than %d", i, seq, prev)
+			}
+		}
+		var pinned int
+		for i := range rb.len {
+			if rb.meta[rb.index(i)].pinned {
+				pinned += 1
+			}
+		}
+		if pinned != rb.pinned {
+			return fmt.Errorf("pinned count %d doesn't match number of pinned values %d", rb.pinned, pinned)
+		}
+	}
+
+	if rb.cost != nil {
+		var total int
+		for i := range rb.len {
+			total += rb.cost(rb.buf[rb.index(i)])
+		}
+		if total != rb.costTotal {
+			return fmt.Errorf("total cost %d doesn't match sum of value costs %d", rb.costTotal, total)
+		}
+	}
+
+	return nil
+}
+
+// debugValidate panics if the ring buffer is invalid, when built with the
+// rbdebug build tag. It assumes the lock is held.
+func (rb *RingBuffer[T]) debugValidate() {
+	if !debugValidation {
+		return
+	}
+
+	if err := rb.validate(); err != nil {
+		panic(fmt.Sprintf("rb: invalid ring buffer: %v", err))
+	}
+}
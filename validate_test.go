@@ -0,0 +1,52 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	evictOdd := rb.EvictionFunc[int](func(incoming int, values rb.View[int]) int {
+		for i := range values.Len() {
+			if values.At(i)%2 == 1 {
+				return i
+			}
+		}
+		return values.Len() - 1
+	})
+
+	rb := rb.NewRingBuffer[int](4)
+	validate := func() {
+		t.Helper()
+		if err := rb.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	validate()
+	for i := range 10 {
+		rb.Add(i)
+		validate()
+	}
+
+	rb.SetEvictionPolicy(evictOdd)
+	for i := range 10 {
+		rb.Add(i)
+		validate()
+	}
+
+	rb.SetCostLimit(func(i int) int { return i }, 20)
+	validate()
+	rb.Add(15)
+	validate()
+
+	rb.Resize(2)
+	validate()
+	rb.Resize(8)
+	validate()
+	rb.Clear()
+	validate()
+}
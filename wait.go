@@ -0,0 +1,105 @@
+package rb
+
+import (
+	"context"
+)
+
+// Generation returns the current generation of the ring buffer, which changes
+// whenever the ring buffer is modified, e.g. by Add, Resize, or Clear.
+func (rb *RingBuffer[T]) Generation() uint64 {
+	rb.lock()
+	defer rb.unlock()
+
+	return rb.gen
+}
+
+// Wait blocks until the generation of the ring buffer differs from since, and
+// returns the new generation. If the context is done first, it returns since
+// and the context error. Pass the result of Generation, or of a previous call
+// to Wait, as since, to efficiently wait for changes in a loop.
+func (rb *RingBuffer[T]) Wait(ctx context.Context, since uint64) (uint64, error) {
+	rb.lock()
+
+	if gen := rb.gen; gen != since {
+		defer rb.unlock()
+		return gen, nil
+	}
+
+	if rb.waitc == nil {
+		rb.waitc = make(chan struct{})
+		rb.setFlag(flagWaiters, true)
+	}
+	c := rb.waitc
+
+	rb.unlock()
+
+	select {
+	case <-c:
+		return rb.Generation(), nil
+	case <-ctx.Done():
+		return since, ctx.Err()
+	}
+}
+
+// changed should be called after every modification. It assumes the lock is
+// held.
+func (rb *RingBuffer[T]) changed() {
+	rb.debugValidate()
+
+	rb.gen += 1
+	if rb.flags.Load()&flagTokens != 0 {
+		rb.token.Store(rb.gen)
+	}
+
+	if rb.waitc != nil {
+		close(rb.waitc)
+		rb.waitc = nil
+		rb.setFlag(flagWaiters, false)
+	}
+
+	if rb.publishEvery > 0 {
+		rb.unpublished += 1
+		if rb.unpublished >= rb.publishEvery {
+			rb.publish()
+		}
+	}
+
+	if rb.watermarks != nil {
+		rb.checkWatermarks()
+	}
+}
+
+// Token identifies a version of the contents of a ring buffer, see Token.
+type Token uint64
+
+// Token returns a token identifying the current contents of the ring buffer.
+// Unlike Generation, it never takes the lock, so it's suitable for use with
+// lock-free reads like Published. Readers which combine more than one read can
+// take a token before they start, and check it with Changed when they're done,
+// to detect that the ring buffer was modified in between, and retry.
+//
+// In the read-optimized mode enabled by PublishEvery, the token identifies the
+// published snapshot, so it only changes when a snapshot is published, and
+// readers of Published don't retry because of modifications they can't see.
+// Otherwise, it changes on every modification. Expiry of TTL values happens
+// lazily, when the lock is taken, so it's not reflected in the token until
+// then.
+func (rb *RingBuffer[T]) Token() Token {
+	if p := rb.published.Load(); p != nil {
+		return Token(p.gen)
+	}
+
+	// Tokens are only tracked once they're used, so that Add doesn't pay for
+	// them otherwise. The first token may be stale, but it still changes with
+	// every subsequent modification, which is all that Changed relies on.
+	if rb.flags.Load()&flagTokens == 0 {
+		rb.setFlag(flagTokens, true)
+	}
+	return Token(rb.token.Load())
+}
+
+// Changed returns true if the ring buffer has been modified since the token was
+// returned by Token. It never takes the lock.
+func (rb *RingBuffer[T]) Changed(tok Token) bool {
+	return rb.Token() != tok
+}
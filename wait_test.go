@@ -0,0 +1,121 @@
+package rb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](3)
+
+	gen := rb.Generation()
+	assertEqual(t, uint64(0), gen)
+
+	// A stale generation returns immediately.
+	rb.Add(1)
+	have, err := rb.Wait(context.Background(), gen)
+	assertEqual(t, error(nil), err)
+	assertEqual(t, uint64(1), have)
+
+	// A current generation blocks until the next change.
+	type result struct {
+		gen uint64
+		err error
+	}
+	results := make(chan result, 1)
+	go func() {
+		gen, err := rb.Wait(context.Background(), have)
+		results <- result{gen, err}
+	}()
+	select {
+	case r := <-results:
+		t.Fatalf("Wait returned %v before a change", r)
+	default:
+	}
+	rb.Clear()
+	r := <-results
+	assertEqual(t, error(nil), r.err)
+	assertEqual(t, uint64(2), r.gen)
+
+	// Or until the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	have, err = rb.Wait(ctx, r.gen)
+	assertEqual(t, true, errors.Is(err, context.Canceled))
+	assertEqual(t, uint64(2), have)
+}
+
+func TestToken(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](3)
+	rb.PublishEvery(1)
+	rb.Add(1)
+
+	// A consistent read, of a snapshot and its overview, retried on change.
+	read := func(modify func()) (vals []int, count, attempts int) {
+		for {
+			attempts++
+			tok := rb.Token()
+			vals = rb.Published()
+			modify()
+			_, _, count = rb.Overview()
+			if !rb.Changed(tok) {
+				return vals, count, attempts
+			}
+		}
+	}
+
+	vals, count, attempts := read(func() {})
+	assertEqual(t, []int{1}, vals)
+	assertEqual(t, 1, count)
+	assertEqual(t, 1, attempts)
+
+	n := 0
+	vals, count, attempts = read(func() {
+		if n++; n == 1 {
+			rb.Add(2) // concurrent modification, during the first attempt only
+		}
+	})
+	assertEqual(t, []int{2, 1}, vals)
+	assertEqual(t, 2, count)
+	assertEqual(t, 2, attempts)
+}
+
+func TestTokenPublishEvery(t *testing.T) {
+	t.Parallel()
+
+	rb := rb.NewRingBuffer[int](5)
+	rb.PublishEvery(3)
+	rb.Add(1)
+
+	// The token identifies the published snapshot, so unpublished writes don't
+	// cause readers of Published to retry.
+	tok := rb.Token()
+	assertEqual(t, []int{}, rb.Published())
+	rb.Add(2)
+	assertEqual(t, false, rb.Changed(tok))
+	assertEqual(t, []int{}, rb.Published())
+
+	rb.Add(3)
+	assertEqual(t, true, rb.Changed(tok))
+	tok = rb.Token()
+	assertEqual(t, []int{3, 2, 1}, rb.Published())
+
+	rb.Flush() // nothing to publish
+	assertEqual(t, false, rb.Changed(tok))
+	rb.Add(4)
+	rb.Flush()
+	assertEqual(t, true, rb.Changed(tok))
+
+	// Without the mode, the token changes on every write.
+	rb.PublishEvery(0)
+	tok = rb.Token()
+	rb.Add(5)
+	assertEqual(t, true, rb.Changed(tok))
+}
@@ -0,0 +1,381 @@
+package rb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// walSuffix is the file extension of WAL segments.
+const walSuffix = ".wal"
+
+// WAL is a ring buffer whose values are also appended to a write-ahead log on
+// disk, so they survive a restart. The ring buffer remains the fast in-memory
+// head, which serves all reads, and the log is only read by OpenWAL, to rebuild
+// the ring buffer.
+//
+// The log is a directory of segment files, named by increasing index. When a
+// segment reaches the segment size, a new segment is started, and the oldest
+// segments are deleted, so that there are at most max segments. Disk usage is
+// therefore bounded by the segment size times max segments, roughly.
+//
+// Each record in a segment is a uvarint length, followed by a 4 byte
+// little-endian CRC-32 (IEEE) checksum of the data, followed by the data,
+// which is a value encoded by the codec.
+type WAL[T any] struct {
+	dir         string
+	codec       Codec[T]
+	segmentSize int64
+	maxSegments int
+	rb          *RingBuffer[T]
+
+	mtx    sync.Mutex // serializes Add, so the log and ring buffer agree
+	f      walFile    // current segment
+	index  int        // of the current segment
+	size   int64      // of the current segment
+	err    error      // most recent error from Add
+	failed error      // if set, the log can't be appended to
+}
+
+// walFile is the subset of *os.File used for the current segment.
+type walFile interface {
+	io.WriteSeeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+var _ Buffer[int] = (*WAL[int])(nil)
+
+// OpenWAL opens or creates the log in dir, and returns a WAL with a ring
+// buffer of size sz, rebuilt from the most recent values in the log. Segments
+// are rotated when they exceed segmentSize bytes, and at most maxSegments are
+// kept, which must be at least 1.
+//
+// A partially written record at the end of the log, e.g. from a crash during
+// Add, is truncated. A corrupt record elsewhere is an error.
+func OpenWAL[T any](dir string, sz int, codec Codec[T], segmentSize int64, maxSegments int) (*WAL[T], error) {
+	if maxSegments < 1 {
+		return nil, errors.New("max segments must be at least 1")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	w := &WAL[T]{
+		dir:         dir,
+		codec:       codec,
+		segmentSize: segmentSize,
+		maxSegments: maxSegments,
+		rb:          NewRingBuffer[T](sz),
+	}
+
+	indexes, err := walSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var valid int64
+	for i, index := range indexes {
+		last := i == len(indexes)-1
+		valid, err = readSegment(w.segmentPath(index), last, func(data []byte) error {
+			val, err := codec.Decode(data)
+			if err != nil {
+				return fmt.Errorf("segment %d: decode value: %w", index, err)
+			}
+			w.rb.Add(val)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(indexes) > 0 {
+		w.index = indexes[len(indexes)-1]
+	}
+	if err := w.openSegment(valid); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// RingBuffer returns the in-memory head of the log. Values added to it directly
+// aren't logged.
+func (w *WAL[T]) RingBuffer() *RingBuffer[T] {
+	return w.rb
+}
+
+// Add appends the value to the log, and then adds it to the ring buffer. If the
+// value can't be logged, it isn't added, and the error is available via Err.
+//
+// A failed write is truncated from the log, so later values aren't lost behind
+// a partial record. If that isn't possible, every subsequent Add fails.
+func (w *WAL[T]) Add(val T) (dropped T, ok bool) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if err := w.append(val); err != nil {
+		w.err = err
+		return dropped, false
+	}
+
+	return w.rb.Add(val)
+}
+
+// Err returns the most recent error from Add, if any.
+func (w *WAL[T]) Err() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return w.err
+}
+
+// Walk implements Buffer.
+func (w *WAL[T]) Walk(fn func(T) error) error { return w.rb.Walk(fn) }
+
+// Take implements Buffer.
+func (w *WAL[T]) Take(n int) ([]T, error) { return w.rb.Take(n) }
+
+// Overview implements Buffer.
+func (w *WAL[T]) Overview() (newest, oldest T, count int) { return w.rb.Overview() }
+
+// Resize implements Buffer. It resizes the ring buffer, but not the log, so
+// the new size should also be passed to OpenWAL on restart.
+func (w *WAL[T]) Resize(sz int) (dropped []T) { return w.rb.Resize(sz) }
+
+// Sync commits the current segment to stable storage.
+func (w *WAL[T]) Sync() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return w.f.Sync()
+}
+
+// Close syncs and closes the current segment. The WAL shouldn't be used after
+// it's closed, except for reads from the ring buffer.
+func (w *WAL[T]) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return errors.Join(w.f.Sync(), w.f.Close())
+}
+
+// append writes the value to the current segment, rotating first if necessary.
+// It assumes w.mtx is held.
+func (w *WAL[T]) append(val T) error {
+	if w.failed != nil {
+		return w.failed
+	}
+
+	data, err := w.codec.Encode(val)
+	if err != nil {
+		return fmt.Errorf("encode value: %w", err)
+	}
+
+	record := binary.AppendUvarint(nil, uint64(len(data)))
+	record = binary.LittleEndian.AppendUint32(record, crc32.ChecksumIEEE(data))
+	record = append(record, data...)
+
+	if w.size > 0 && w.size+int64(len(record)) > w.segmentSize {
+		if err := w.rotate(); err != nil {
+			return fmt.Errorf("rotate: %w", err)
+		}
+	}
+
+	if n, err := w.f.Write(record); err != nil {
+		if n > 0 {
+			if err := w.truncate(); err != nil {
+				w.failed = fmt.Errorf("truncate partial record: %w", err)
+			}
+		}
+		return fmt.Errorf("write record: %w", err)
+	}
+
+	w.size += int64(len(record))
+	return nil
+}
+
+// truncate removes anything written to the current segment after w.size. It
+// assumes w.mtx is held.
+func (w *WAL[T]) truncate() error {
+	if err := w.f.Truncate(w.size); err != nil {
+		return err
+	}
+	_, err := w.f.Seek(w.size, io.SeekStart)
+	return err
+}
+
+// rotate closes the current segment, starts the next one, and deletes the
+// oldest segments beyond the max. It assumes w.mtx is held.
+func (w *WAL[T]) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+
+	w.index++
+	if err := w.openSegment(0); err != nil {
+		return err
+	}
+
+	indexes, err := walSegments(w.dir)
+	if err != nil {
+		return err
+	}
+	for len(indexes) > w.maxSegments {
+		if err := os.Remove(w.segmentPath(indexes[0])); err != nil {
+			return err
+		}
+		indexes = indexes[1:]
+	}
+
+	return nil
+}
+
+// openSegment opens the segment at the current index for appending, after
+// truncating it to size bytes.
+func (w *WAL[T]) openSegment(size int64) error {
+	f, err := os.OpenFile(w.segmentPath(w.index), os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+
+	w.f, w.size = f, size
+	return nil
+}
+
+func (w *WAL[T]) segmentPath(index int) string {
+	return walSegmentPath(w.dir, index)
+}
+
+// ReadWAL calls fn for every value in the log in dir, oldest first, without
+// modifying it. It's useful for inspecting a log offline. As with OpenWAL, a
+// partially written record at the end of the log is ignored, and a corrupt
+// record elsewhere is an error.
+func ReadWAL[T any](dir string, codec Codec[T], fn func(T) error) error {
+	indexes, err := walSegments(dir)
+	if err != nil {
+		return err
+	}
+
+	for i, index := range indexes {
+		if _, err := readSegment(walSegmentPath(dir, index), i == len(indexes)-1, func(data []byte) error {
+			val, err := codec.Decode(data)
+			if err != nil {
+				return fmt.Errorf("segment %d: decode value: %w", index, err)
+			}
+			return fn(val)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walSegments returns the indexes of the segments in dir, in increasing order.
+func walSegments(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []int
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), walSuffix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		index, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+
+	return indexes, nil
+}
+
+func walSegmentPath(dir string, index int) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", index, walSuffix))
+}
+
+// readSegment calls fn with the data of every record in the segment at path,
+// and returns the size of the valid prefix of the segment. If the segment is
+// the last one, a record which is cut short by the end of the file is a torn
+// write, which ends it. Anything else, e.g. a checksum mismatch, is an error.
+func readSegment(path string, last bool, fn func([]byte) error) (valid int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	for {
+		record, data, err := readRecord(br)
+		switch {
+		case err == io.EOF:
+			return valid, nil
+		case errors.Is(err, io.ErrUnexpectedEOF) && last:
+			return valid, nil
+		case err != nil:
+			return valid, fmt.Errorf("%s: offset %d: %w", filepath.Base(path), valid, err)
+		}
+
+		if err := fn(data); err != nil {
+			return valid, err
+		}
+		valid += record
+	}
+}
+
+// readRecord reads a single record, and returns its total size and data. It
+// returns io.EOF only if there are no more records.
+func readRecord(br *bufio.Reader) (size int64, data []byte, err error) {
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		if err == io.EOF {
+			return 0, nil, io.EOF
+		}
+		return 0, nil, fmt.Errorf("read length: %w", err)
+	}
+
+	var sum [4]byte
+	if _, err := io.ReadFull(br, sum[:]); err != nil {
+		return 0, nil, fmt.Errorf("read checksum: %w", io.ErrUnexpectedEOF)
+	}
+
+	var buf bytes.Buffer // grows as data is read, in case n is corrupt
+	if _, err := io.CopyN(&buf, br, int64(n)); err != nil {
+		return 0, nil, fmt.Errorf("read data: %w", io.ErrUnexpectedEOF)
+	}
+	data = buf.Bytes()
+
+	if crc32.ChecksumIEEE(data) != binary.LittleEndian.Uint32(sum[:]) {
+		return 0, nil, errors.New("checksum mismatch")
+	}
+
+	size = int64(len(binary.AppendUvarint(nil, n))) + int64(len(sum)) + int64(n)
+	return size, data, nil
+}
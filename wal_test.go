@@ -0,0 +1,120 @@
+package rb_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestWAL(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	codec := rb.JSONCodec[int]{}
+
+	// Each record is 1 byte of length, 4 of checksum, and 1 or 2 of data, so
+	// segments of 20 bytes hold 3 or 2 records.
+	w, err := rb.OpenWAL[int](dir, 3, codec, 20, 2)
+	assertEqual(t, error(nil), err)
+	for i := 1; i <= 10; i++ {
+		w.Add(i)
+	}
+	assertEqual(t, error(nil), w.Err())
+	assertEqual(t, error(nil), w.Close())
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.wal"))
+	assertEqual(t, 2, len(segments))
+
+	// The log keeps more than the ring buffer, bounded by the segment limits.
+	var logged []int
+	assertEqual(t, error(nil), rb.ReadWAL[int](dir, codec, func(v int) error {
+		logged = append(logged, v)
+		return nil
+	}))
+	assertEqual(t, []int{7, 8, 9, 10}, logged)
+
+	// Simulate a crash during a write, which leaves a partial record.
+	f, err := os.OpenFile(segments[len(segments)-1], os.O_WRONLY|os.O_APPEND, 0)
+	assertEqual(t, error(nil), err)
+	f.Write([]byte{2, 0xde, 0xad})
+	f.Close()
+
+	// On restart, the ring buffer is rebuilt from the tail of the log, and the
+	// partial record is truncated.
+	w, err = rb.OpenWAL[int](dir, 3, codec, 20, 2)
+	assertEqual(t, error(nil), err)
+	vals, _ := w.Take(3)
+	assertEqual(t, []int{10, 9, 8}, vals)
+
+	w.Add(11)
+	assertEqual(t, error(nil), w.Close())
+
+	logged = logged[:0]
+	rb.ReadWAL[int](dir, codec, func(v int) error {
+		logged = append(logged, v)
+		return nil
+	})
+	assertEqual(t, []int{7, 8, 9, 10, 11}, logged)
+}
+
+func TestWALShortWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	codec := rb.JSONCodec[int]{}
+
+	w, err := rb.OpenWAL[int](dir, 5, codec, 1024, 2)
+	assertEqual(t, error(nil), err)
+	w.Add(1)
+	w.Add(2)
+
+	// The partial record is truncated, so the values after it aren't lost.
+	rb.ShortWriteWAL(w, 3)
+	_, ok := w.Add(3)
+	assertEqual(t, false, ok)
+	assertEqual(t, true, errors.Is(w.Err(), io.ErrShortWrite))
+	w.Add(4)
+	assertEqual(t, error(nil), w.Close())
+
+	w, err = rb.OpenWAL[int](dir, 5, codec, 1024, 2)
+	assertEqual(t, error(nil), err)
+	vals, _ := w.Take(5)
+	assertEqual(t, []int{4, 2, 1}, vals)
+	assertEqual(t, error(nil), w.Close())
+}
+
+func TestWALCorrupt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	codec := rb.JSONCodec[int]{}
+
+	w, err := rb.OpenWAL[int](dir, 5, codec, 1024, 2)
+	assertEqual(t, error(nil), err)
+	for i := 1; i <= 5; i++ {
+		w.Add(i)
+	}
+	assertEqual(t, error(nil), w.Close())
+
+	// Flip a byte in the data of the third record, each of which is 6 bytes.
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.wal"))
+	assertEqual(t, 1, len(segments))
+	data, err := os.ReadFile(segments[0])
+	assertEqual(t, error(nil), err)
+	data[2*6+5] ^= 0xff
+	assertEqual(t, error(nil), os.WriteFile(segments[0], data, 0o644))
+
+	// A corrupt record in the middle of the last segment isn't a torn write,
+	// so it's an error, and the records after it aren't truncated.
+	_, err = rb.OpenWAL[int](dir, 5, codec, 1024, 2)
+	assertEqual(t, true, err != nil)
+	assertEqual(t, true, rb.ReadWAL[int](dir, codec, func(int) error { return nil }) != nil)
+
+	after, err := os.ReadFile(segments[0])
+	assertEqual(t, error(nil), err)
+	assertEqual(t, data, after)
+}
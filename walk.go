@@ -0,0 +1,111 @@
+package rb
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Walks and transactions hold the lock while calling user functions, so a
+// function which calls a method on a ring buffer it holds would deadlock. Such
+// calls panic instead, which means telling the holding goroutine apart from
+// others, which simply wait for the lock.
+//
+// Go doesn't expose goroutine IDs. Instead, every ring buffer counts the
+// callbacks which hold it, and a global counter tracks the callbacks in
+// progress, across all ring buffers. A goroutine knows how many callbacks it's
+// in from the callbackFrame calls on its stack, and if that's all of them, and
+// the ring buffer is held throughout the check, then the caller holds it.
+// Otherwise, e.g. if another goroutine is in a callback at the same time, the
+// caller can't be told apart from other goroutines, and waits for the lock as
+// usual.
+
+// callbacks is the number of walks and transactions in progress, across all
+// ring buffers.
+var callbacks atomic.Int64
+
+// Reasons a ring buffer is held, see hold.
+const (
+	heldWalk uint32 = iota + 1
+	heldTx
+)
+
+// walk locks the ring buffer and calls fn, during which the ring buffer is
+// held by the calling goroutine, see checkHeld.
+func (rb *RingBuffer[T]) walk(fn func()) {
+	rb.lock()
+	defer rb.unlock()
+
+	callbacks.Add(1)
+	defer callbacks.Add(-1)
+
+	rb.hold(heldWalk)
+	defer rb.unhold()
+
+	callbackFrame(fn)
+}
+
+// hold marks the ring buffer as held by a callback, for the given reason. It
+// assumes the lock is held, and must be followed by unhold.
+func (rb *RingBuffer[T]) hold(reason uint32) {
+	rb.heldFor.Store(reason)
+	rb.holds.Add(1)
+}
+
+// unhold marks the ring buffer as no longer held by a callback.
+func (rb *RingBuffer[T]) unhold() {
+	rb.holds.Add(1)
+}
+
+// checkHeld panics if the caller is in a callback which holds the ring buffer,
+// as acquiring the lock would deadlock. It must only be called after failing to
+// acquire it.
+func (rb *RingBuffer[T]) checkHeld() {
+	// holds is odd while the ring buffer is held, and changes when it's
+	// released, so if it's unchanged after the other checks, then the same
+	// callback held it throughout.
+	h := rb.holds.Load()
+	if h%2 == 0 {
+		return
+	}
+
+	depth := callbackDepth()
+	if depth == 0 || callbacks.Load() != depth || rb.holds.Load() != h {
+		return
+	}
+
+	if rb.heldFor.Load() == heldTx {
+		panic("rb: ring buffer method called from within a transaction on the same ring buffer, which would deadlock (use AddTx)")
+	}
+	panic("rb: ring buffer method called from within a walk of the same ring buffer, which would deadlock")
+}
+
+// callbackFrame calls fn. It isn't inlined, so it appears on the stack, which
+// is how callbackDepth counts callbacks.
+//
+//go:noinline
+func callbackFrame(fn func()) {
+	fn()
+}
+
+// callbackFramePC is the return address of the call to fn in callbackFrame.
+var callbackFramePC = func() uintptr {
+	var pc [1]uintptr
+	callbackFrame(func() { runtime.Callers(2, pc[:]) })
+	return pc[0]
+}()
+
+// callbackDepth returns the number of callbacks the calling goroutine is in.
+func callbackDepth() (depth int64) {
+	var pcs [64]uintptr
+	for skip := 2; ; skip += len(pcs) {
+		n := runtime.Callers(skip, pcs[:])
+		for _, pc := range pcs[:n] {
+			if pc == callbackFramePC {
+				depth++
+			}
+		}
+		if n < len(pcs) {
+			return depth
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package rb
+
+// OnHighWater registers fn to be called whenever the fill of the ring buffer,
+// i.e. the number of values as a fraction of its capacity, rises from below the
+// given level to at or above it. That lets producers shed load, or switch to
+// sampling, before values start to be dropped. Use a level below 1, since a
+// full ring buffer stays full. If fn is nil, the callback is removed.
+//
+// The function is called after the lock is released, by the goroutine which
+// made the change, so it may call methods on the ring buffer, but it should be
+// fast, e.g. setting an atomic flag, as it delays that goroutine.
+func (rb *RingBuffer[T]) OnHighWater(level float64, fn func()) {
+	rb.lock()
+	defer rb.unlock()
+
+	w := rb.watermarksLocked()
+	w.high, w.onHigh = level, fn
+}
+
+// OnLowWater registers fn to be called whenever the fill of the ring buffer
+// falls from above the given level to at or below it, e.g. to stop shedding
+// load after OnHighWater. Fill falls when values are removed, e.g. by TTL
+// expiry or Clear, or when the ring buffer is grown, not when values are
+// overwritten. It has the same semantics as OnHighWater otherwise.
+func (rb *RingBuffer[T]) OnLowWater(level float64, fn func()) {
+	rb.lock()
+	defer rb.unlock()
+
+	w := rb.watermarksLocked()
+	w.low, w.onLow = level, fn
+}
+
+type watermarks struct {
+	high, low     float64
+	onHigh, onLow func()
+	fill          float64  // fill as of the last change
+	fired         []func() // callbacks to call once the lock is released
+}
+
+// watermarksLocked returns the watermarks, creating them if necessary. It
+// assumes the lock is held.
+func (rb *RingBuffer[T]) watermarksLocked() *watermarks {
+	if rb.watermarks == nil {
+		rb.watermarks = &watermarks{fill: rb.fill()}
+		rb.setFlag(flagWatermarks, true)
+	}
+	return rb.watermarks
+}
+
+// checkWatermarks records the watermark callbacks for any level crossed since
+// the last change, which are called by unlock. It assumes the lock is held.
+func (rb *RingBuffer[T]) checkWatermarks() {
+	w := rb.watermarks
+	prev, cur := w.fill, rb.fill()
+	w.fill = cur
+
+	if w.onHigh != nil && prev < w.high && cur >= w.high {
+		w.fired = append(w.fired, w.onHigh)
+	}
+	if w.onLow != nil && prev > w.low && cur <= w.low {
+		w.fired = append(w.fired, w.onLow)
+	}
+}
+
+// fill returns the number of values as a fraction of the capacity. It assumes
+// the lock is held.
+func (rb *RingBuffer[T]) fill() float64 {
+	if len(rb.buf) == 0 {
+		return 0
+	}
+	return float64(rb.len) / float64(len(rb.buf))
+}
@@ -0,0 +1,71 @@
+package rb_test
+
+import (
+	"testing"
+
+	"github.com/peterbourgon/rb"
+)
+
+func TestRingBufferWatermarks(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+	buf := rb.NewRingBuffer[int](10)
+	buf.OnHighWater(0.8, func() { events = append(events, "high") })
+	buf.OnLowWater(0.2, func() { events = append(events, "low") })
+
+	for i := range 20 {
+		buf.Add(i)
+	}
+	assertEqual(t, []string{"high"}, events) // once, when crossed
+
+	buf.TrimOldest(0.5)
+	assertEqual(t, []string{"high"}, events)
+	buf.TrimOldest(0.6)
+	assertEqual(t, []string{"high", "low"}, events)
+
+	// Growing the capacity lowers the fill.
+	for range 6 {
+		buf.Add(0)
+	}
+	assertEqual(t, []string{"high", "low", "high"}, events)
+	buf.Resize(100)
+	assertEqual(t, []string{"high", "low", "high", "low"}, events)
+
+	buf.OnHighWater(0, nil)
+	buf.OnLowWater(0, nil)
+	buf.Clear()
+	for range 100 {
+		buf.Add(0)
+	}
+	assertEqual(t, 4, len(events))
+}
+
+func TestRingBufferWatermarksReentrant(t *testing.T) {
+	t.Parallel()
+
+	// Callbacks are called without the lock held, so they can use the ring
+	// buffer.
+	var counts []int
+	buf := rb.NewRingBuffer[int](4)
+	buf.OnHighWater(0.5, func() {
+		counts = append(counts, buf.Stats().Count)
+		buf.SetSampleRate(1)
+	})
+	buf.OnLowWater(0.25, func() { buf.Add(-1) })
+	buf.Add(1)
+	buf.Add(2)
+	buf.Clear()
+	assertEqual(t, []int{2}, counts)
+	vals, _ := buf.Take(4)
+	assertEqual(t, []int{-1}, vals)
+
+	// A callback which panics doesn't leave the ring buffer locked.
+	buf = rb.NewRingBuffer[int](4)
+	buf.OnHighWater(0.5, func() { panic("high water") })
+	buf.Add(1)
+	assertPanics(t, func() { buf.Add(2) })
+	buf.Add(3)
+	vals, _ = buf.Take(4)
+	assertEqual(t, []int{3, 2, 1}, vals)
+}